@@ -0,0 +1,434 @@
+package ai
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/user/commit-ai/git"
+)
+
+// Tipos de mudança incompatível na API pública
+const (
+	APIRemoved         = "removed" // Símbolo exportado removido
+	APIChanged         = "changed" // Assinatura ou tipo alterado
+	APIInterfaceMethod = "method"  // Método novo em interface exportada (quebra as implementações)
+)
+
+// APIChange descreve uma mudança incompatível na API pública de um pacote Go
+type APIChange struct {
+	Kind      string // APIRemoved, APIChanged ou APIInterfaceMethod
+	Symbol    string // Símbolo afetado, qualificado pelo pacote (ex.: "func ai.NewStyle")
+	Interface string // Interface que recebeu o método (apenas APIInterfaceMethod)
+	Before    string // Assinatura anterior
+	After     string // Assinatura nova
+}
+
+// breakingFooter reconhece um rodapé BREAKING CHANGE já presente na mensagem
+var breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// apiSymbol é uma declaração exportada e a sua assinatura normalizada
+type apiSymbol struct {
+	signature string
+	receiver  string // Receptor do método ("T" ou "*T")
+	parent    string // Tipo a que o campo ou método pertence
+	iface     bool   // Indica se é método (ou interface embutida) de uma interface
+	untyped   bool   // Constante ou variável sem tipo explícito
+}
+
+// DetectBreakingChanges compara a API exportada das versões anterior e nova dos arquivos .go
+// alterados e retorna as mudanças incompatíveis. Arquivos de teste, pacotes main, pacotes
+// internal e arquivos que não compilam em alguma das versões são ignorados.
+func DetectBreakingChanges(repo *git.Repository, cs *git.ChangeSet) ([]APIChange, error) {
+	if cs.IsEmpty() {
+		return nil, nil
+	}
+
+	before := make(map[string]map[string]apiSymbol)
+	after := make(map[string]map[string]apiSymbol)
+	for _, f := range cs.Files {
+		oldPath := f.OldPath
+		if oldPath == "" {
+			oldPath = f.Path
+		}
+		if f.Binary || (!isPublicGoFile(oldPath) && !isPublicGoFile(f.Path)) {
+			continue
+		}
+
+		oldSrc, newSrc, err := repo.FileVersions(cs, f)
+		if err != nil {
+			return nil, err
+		}
+		oldFile, oldOK := parseGoFile(oldPath, oldSrc)
+		newFile, newOK := parseGoFile(f.Path, newSrc)
+		if !oldOK || !newOK {
+			continue
+		}
+		if oldFile != nil && isPublicGoFile(oldPath) {
+			collectAPI(oldFile, symbolsFor(before, path.Dir(oldPath)))
+		}
+		if newFile != nil && isPublicGoFile(f.Path) {
+			collectAPI(newFile, symbolsFor(after, path.Dir(f.Path)))
+		}
+	}
+
+	var changes []APIChange
+	for dir, old := range before {
+		changes = append(changes, diffAPI(old, after[dir])...)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Symbol < changes[j].Symbol
+	})
+	return changes, nil
+}
+
+// isPublicGoFile indica se o arquivo pode fazer parte da API pública de um pacote
+func isPublicGoFile(file string) bool {
+	if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if dir == "internal" || dir == "testdata" || dir == "vendor" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseGoFile interpreta o código-fonte; ok é false quando ele não compila.
+// Conteúdo nil (arquivo inexistente nessa versão) retorna nil com ok verdadeiro.
+func parseGoFile(file string, src []byte) (parsed *ast.File, ok bool) {
+	if src == nil {
+		return nil, true
+	}
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+	if parsed.Name.Name == "main" {
+		return nil, true
+	}
+	return parsed, true
+}
+
+// symbolsFor retorna o mapa de símbolos do diretório, criando-o se necessário
+func symbolsFor(packages map[string]map[string]apiSymbol, dir string) map[string]apiSymbol {
+	if packages[dir] == nil {
+		packages[dir] = make(map[string]apiSymbol)
+	}
+	return packages[dir]
+}
+
+// collectAPI registra as declarações exportadas do arquivo
+func collectAPI(file *ast.File, into map[string]apiSymbol) {
+	pkg := file.Name.Name
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				into["func "+pkg+"."+d.Name.Name] = apiSymbol{signature: funcSignature(d.Type)}
+				continue
+			}
+			recv, pointer := receiverName(d.Recv.List[0].Type)
+			if !ast.IsExported(recv) {
+				continue
+			}
+			receiver := recv
+			if pointer {
+				receiver = "*" + recv
+			}
+			into["method "+pkg+"."+recv+"."+d.Name.Name] = apiSymbol{signature: funcSignature(d.Type), receiver: receiver, parent: pkg + "." + recv}
+
+		case *ast.GenDecl:
+			var lastType ast.Expr
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					collectType(pkg, s, into)
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+						// Numa sequência com iota, a especificação sem tipo nem valor repete a anterior
+						if s.Type == nil && len(s.Values) == 0 {
+							s.Type = lastType
+						}
+						lastType = s.Type
+					}
+					for _, name := range s.Names {
+						if name.IsExported() {
+							into[kind+" "+pkg+"."+name.Name] = apiSymbol{signature: exprString(s.Type), untyped: s.Type == nil}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// collectType registra um tipo exportado com os seus campos ou métodos exportados
+func collectType(pkg string, spec *ast.TypeSpec, into map[string]apiSymbol) {
+	if !spec.Name.IsExported() {
+		return
+	}
+	qualified := pkg + "." + spec.Name.Name
+	params := fieldTypes(spec.TypeParams)
+	if params != "" {
+		params = "[" + params + "]"
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		into["type "+qualified] = apiSymbol{signature: "struct" + params}
+		for _, field := range t.Fields.List {
+			signature := exprString(field.Type)
+			if len(field.Names) == 0 {
+				if name := embeddedName(field.Type); ast.IsExported(name) {
+					into["field "+qualified+"."+name] = apiSymbol{signature: signature, parent: qualified}
+				}
+				continue
+			}
+			for _, name := range field.Names {
+				if name.IsExported() {
+					into["field "+qualified+"."+name.Name] = apiSymbol{signature: signature, parent: qualified}
+				}
+			}
+		}
+
+	case *ast.InterfaceType:
+		into["type "+qualified] = apiSymbol{signature: "interface" + params}
+		for _, method := range t.Methods.List {
+			if len(method.Names) == 0 {
+				embedded := exprString(method.Type)
+				into["embed "+qualified+"."+embedded] = apiSymbol{signature: embedded, parent: qualified, iface: true}
+				continue
+			}
+			ft, ok := method.Type.(*ast.FuncType)
+			if !ok {
+				continue
+			}
+			for _, name := range method.Names {
+				if name.IsExported() {
+					into["method "+qualified+"."+name.Name] = apiSymbol{signature: funcSignature(ft), parent: qualified, iface: true}
+				}
+			}
+		}
+
+	default:
+		signature := exprString(spec.Type)
+		if spec.Assign.IsValid() {
+			signature = "= " + signature
+		}
+		into["type "+qualified] = apiSymbol{signature: params + signature}
+	}
+}
+
+// diffAPI compara as APIs de um pacote e retorna as mudanças incompatíveis
+func diffAPI(before map[string]apiSymbol, after map[string]apiSymbol) []APIChange {
+	removedType := func(parent string) bool {
+		_, existed := before["type "+parent]
+		_, exists := after["type "+parent]
+		return existed && !exists
+	}
+
+	var changes []APIChange
+	for key, old := range before {
+		current, ok := after[key]
+		switch {
+		case !ok:
+			// Campos e métodos de um tipo removido são cobertos pela remoção do tipo
+			if old.parent == "" || !removedType(old.parent) {
+				changes = append(changes, APIChange{Kind: APIRemoved, Symbol: key, Before: old.describe()})
+			}
+		case old.signature != current.signature && !old.untyped && !current.untyped,
+			// Trocar o receptor de T para *T tira o método do conjunto de métodos de T
+			!strings.HasPrefix(old.receiver, "*") && strings.HasPrefix(current.receiver, "*"):
+			changes = append(changes, APIChange{Kind: APIChanged, Symbol: key, Before: old.describe(), After: current.describe()})
+		}
+	}
+
+	for key, current := range after {
+		if _, ok := before[key]; ok || !current.iface {
+			continue
+		}
+		if parent, existed := before["type "+current.parent]; existed && strings.HasPrefix(parent.signature, "interface") {
+			changes = append(changes, APIChange{Kind: APIInterfaceMethod, Symbol: key, Interface: current.parent, After: current.signature})
+		}
+	}
+	return changes
+}
+
+// describe retorna a assinatura para exibição, com o receptor quando for um método
+func (s apiSymbol) describe() string {
+	if s.receiver == "" {
+		return s.signature
+	}
+	return "(" + s.receiver + ") " + s.signature
+}
+
+// funcSignature monta a assinatura da função apenas com os tipos, sem os nomes dos parâmetros
+func funcSignature(ft *ast.FuncType) string {
+	signature := "func"
+	if params := fieldTypes(ft.TypeParams); params != "" {
+		signature += "[" + params + "]"
+	}
+	signature += "(" + fieldTypes(ft.Params) + ")"
+
+	results := fieldTypes(ft.Results)
+	switch {
+	case results == "":
+	case ft.Results.NumFields() == 1:
+		signature += " " + results
+	default:
+		signature += " (" + results + ")"
+	}
+	return signature
+}
+
+// fieldTypes lista os tipos de uma lista de campos, repetindo o tipo para cada nome
+func fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+	var list []string
+	for _, field := range fields.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			list = append(list, exprString(field.Type))
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// receiverName retorna o nome do tipo receptor e se ele é um ponteiro
+func receiverName(expr ast.Expr) (name string, pointer bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr, pointer = star.X, true
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name, pointer
+	}
+	return "", pointer
+}
+
+// embeddedName retorna o nome de um campo embutido (o nome do tipo, sem pacote ou ponteiro)
+func embeddedName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// exprString formata uma expressão de tipo (vazia para nil)
+func exprString(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	return types.ExprString(expr)
+}
+
+// breakingPhrases contém os modelos de frase das mudanças incompatíveis, por idioma
+var breakingPhrases = map[string]map[string]string{
+	"en":    {APIRemoved: "remove %s", APIChanged: "change %s", APIInterfaceMethod: "add %s to interface %s"},
+	"es":    {APIRemoved: "elimina %s", APIChanged: "cambia %s", APIInterfaceMethod: "agrega %s a la interfaz %s"},
+	"fr":    {APIRemoved: "supprime %s", APIChanged: "modifie %s", APIInterfaceMethod: "ajoute %s à l'interface %s"},
+	"de":    {APIRemoved: "entferne %s", APIChanged: "ändere %s", APIInterfaceMethod: "füge %s zu Interface %s hinzu"},
+	"pt-br": {APIRemoved: "remove %s", APIChanged: "altera %s", APIInterfaceMethod: "adiciona %s à interface %s"},
+}
+
+// Describe descreve a mudança em poucas palavras no idioma solicitado
+func (c APIChange) Describe(language string) string {
+	phrases, ok := breakingPhrases[language]
+	if !ok {
+		phrases = breakingPhrases["pt-br"]
+	}
+	if c.Kind == APIInterfaceMethod {
+		kind, symbol, _ := strings.Cut(c.Symbol, " ")
+		return fmt.Sprintf(phrases[c.Kind], kind+" "+strings.TrimPrefix(symbol, c.Interface+"."), c.Interface)
+	}
+	return fmt.Sprintf(phrases[c.Kind], c.Symbol)
+}
+
+// markBreaking marca a mensagem como incompatível: acrescenta "!" ao cabeçalho no formato
+// Conventional Commits e o rodapé BREAKING CHANGE, se ainda não houver um
+func markBreaking(msg string, changes []APIChange, language string) string {
+	header, rest, hasRest := strings.Cut(msg, "\n")
+	if m := headerPattern.FindStringSubmatch(header); m != nil && isConventionalType(m[1]) && m[3] == "" {
+		header = m[1]
+		if m[2] != "" {
+			header += "(" + m[2] + ")"
+		}
+		header += "!: " + m[4]
+		msg = header
+		if hasRest {
+			msg += "\n" + rest
+		}
+	}
+
+	if breakingFooter.MatchString(msg) {
+		return msg
+	}
+	descriptions := make([]string, len(changes))
+	for i, c := range changes {
+		descriptions[i] = c.Describe(language)
+	}
+	return appendFooter(msg, "BREAKING CHANGE: "+strings.Join(descriptions, "; "))
+}
+
+// getBreakingInstructions retorna, no idioma solicitado, as mudanças incompatíveis na API
+// e o pedido para marcá-las na mensagem
+func getBreakingInstructions(changes []APIChange, language string) string {
+	var list strings.Builder
+	for _, c := range changes {
+		list.WriteString("\n- " + c.Describe(language))
+		switch {
+		case c.Before != "" && c.After != "":
+			fmt.Fprintf(&list, ": %s -> %s", c.Before, c.After)
+		case c.After != "":
+			fmt.Fprintf(&list, ": %s", c.After)
+		}
+	}
+
+	switch language {
+	case "en":
+		return "\n\nThese changes break the public Go API:" + list.String() + "\n\nMark the commit as a breaking change: add \"!\" after the type/scope and a \"BREAKING CHANGE: <description>\" footer explaining what users must change."
+	case "es":
+		return "\n\nEstos cambios rompen la API pública de Go:" + list.String() + "\n\nMarca el commit como un cambio incompatible: agrega \"!\" después del tipo/alcance y un pie \"BREAKING CHANGE: <descripción>\" explicando qué deben cambiar los usuarios."
+	case "fr":
+		return "\n\nCes modifications cassent l'API Go publique:" + list.String() + "\n\nMarquez le commit comme un changement incompatible: ajoutez \"!\" après le type/la portée et un pied de page \"BREAKING CHANGE: <description>\" expliquant ce que les utilisateurs doivent changer."
+	case "de":
+		return "\n\nDiese Änderungen brechen die öffentliche Go-API:" + list.String() + "\n\nKennzeichnen Sie den Commit als inkompatible Änderung: fügen Sie \"!\" nach Typ/Bereich und eine Fußzeile \"BREAKING CHANGE: <Beschreibung>\" hinzu, die erklärt, was Benutzer ändern müssen."
+	default: // Padrão é português pt-br
+		return "\n\nEstas mudanças quebram a API pública Go:" + list.String() + "\n\nMarque o commit como uma mudança incompatível: adicione \"!\" após o tipo/escopo e um rodapé \"BREAKING CHANGE: <descrição>\" explicando o que os usuários precisam mudar."
+	}
+}
@@ -0,0 +1,354 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/user/commit-ai/git"
+)
+
+const (
+	// DefaultContextWindow é a janela de contexto assumida para modelos desconhecidos
+	DefaultContextWindow = 8192
+
+	// maxPromptTokens limita o tamanho do prompt mesmo em modelos com janelas enormes,
+	// para manter custo e latência razoáveis
+	maxPromptTokens = 32000
+
+	// promptReserveTokens é reservado para as instruções do prompt e a mensagem de sistema
+	promptReserveTokens = 1500
+
+	// bytesPerToken é a estimativa usada para converter texto em tokens
+	bytesPerToken = 4
+)
+
+// contextWindows associa prefixos de nomes de modelos ao tamanho da janela de contexto.
+// Prefixos mais longos têm precedência.
+var contextWindows = map[string]int{
+	"gpt-3.5-turbo":      16385,
+	"gpt-4":              8192,
+	"gpt-4-turbo":        128000,
+	"gpt-4o":             128000,
+	"gpt-4.1":            1000000,
+	"o1":                 128000,
+	"o3":                 200000,
+	"openai/gpt-4-turbo": 128000,
+	"openai/gpt-4o":      128000,
+	"gemini-1.5":         1000000,
+	"gemini-2":           1000000,
+	"claude-":            200000,
+	"anthropic/claude-":  200000,
+	"deepseek-":          64000,
+	"grok-":              128000,
+	"llama3":             8192,
+	"llama3.1":           128000,
+	"llama3.2":           128000,
+	"qwen2.5":            32768,
+	"mistral":            32768,
+	"codellama":          16384,
+}
+
+// ContextWindow retorna o tamanho da janela de contexto do modelo, em tokens
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+	best, bestLen := DefaultContextWindow, 0
+	for prefix, size := range contextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > bestLen {
+			best, bestLen = size, len(prefix)
+		}
+	}
+	return best
+}
+
+// EstimateTokens estima a quantidade de tokens de um texto
+func EstimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
+
+// promptBudget retorna quantos tokens do prompt podem ser usados pelas alterações
+func (o ProviderOptions) promptBudget() int {
+	window := o.ContextWindow
+	if window <= 0 {
+		window = ContextWindow(o.Model)
+	}
+	if window > maxPromptTokens {
+		window = maxPromptTokens
+	}
+	budget := window - o.maxTokens() - promptReserveTokens
+	if budget < 512 {
+		budget = 512
+	}
+	return budget
+}
+
+// lockfiles são arquivos gerados por gerenciadores de dependências
+var lockfiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"Pipfile.lock":      true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+}
+
+// lowValueReason indica por que o conteúdo de um arquivo tem pouco valor para a mensagem
+// (lockfile, código gerado ou dependência vendorizada). Retorna vazio para arquivos comuns.
+func lowValueReason(f git.FileChange) string {
+	base := path.Base(f.Path)
+	switch {
+	case lockfiles[base]:
+		return "lockfile"
+	case strings.HasPrefix(f.Path, "vendor/"), strings.Contains(f.Path, "/vendor/"),
+		strings.HasPrefix(f.Path, "node_modules/"), strings.Contains(f.Path, "/node_modules/"),
+		strings.HasPrefix(f.Path, "third_party/"):
+		return "dependência vendorizada"
+	case strings.HasSuffix(base, ".pb.go"), strings.HasSuffix(base, "_gen.go"),
+		strings.HasPrefix(base, "zz_generated"), strings.Contains(base, ".generated."),
+		strings.HasSuffix(base, ".min.js"), strings.HasSuffix(base, ".min.css"),
+		strings.HasSuffix(base, ".map"):
+		return "código gerado"
+	}
+
+	// Cabeçalho padrão de código gerado ("Code generated ... DO NOT EDIT.")
+	for _, h := range f.Hunks {
+		for i, line := range h.Lines {
+			if i >= 5 {
+				break
+			}
+			if strings.Contains(line, "Code generated") && strings.Contains(line, "DO NOT EDIT") {
+				return "código gerado"
+			}
+		}
+	}
+	return ""
+}
+
+// isWhitespaceOnly indica se o trecho altera apenas espaços em branco
+func isWhitespaceOnly(h git.Hunk) bool {
+	counts := make(map[string]int)
+	changed := false
+	for _, line := range h.Lines {
+		if len(line) == 0 {
+			continue
+		}
+		normalized := strings.Join(strings.Fields(line[1:]), "")
+		switch line[0] {
+		case '+':
+			counts[normalized]++
+			changed = true
+		case '-':
+			counts[normalized]--
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// budgetedFile acompanha o que será incluído de cada arquivo no prompt
+type budgetedFile struct {
+	file    git.FileChange
+	hunks   []git.Hunk
+	omitted string // Motivo da omissão total ou parcial do conteúdo
+}
+
+func (b *budgetedFile) render() string {
+	f := b.file
+	f.Hunks = b.hunks
+	return renderFileDiff(f)
+}
+
+// RenderChangesWithBudget converte um ChangeSet em texto para o prompt sem exceder o
+// orçamento de tokens informado. Quando necessário, descarta primeiro trechos que só
+// alteram espaços, depois lockfiles, código gerado e dependências vendorizadas, e por fim
+// divide o orçamento restante de forma justa entre os arquivos, cortando sempre em limites
+// de trecho ou de linha. Os arquivos omitidos são resumidos no final.
+func RenderChangesWithBudget(cs *git.ChangeSet, budget int) string {
+	text, _ := renderWithBudget(cs, budget)
+	return text
+}
+
+// renderWithBudget implementa RenderChangesWithBudget e indica se foi preciso cortar
+// conteúdo relevante (a divisão justa do orçamento), e não apenas espaços em branco,
+// lockfiles, código gerado e dependências vendorizadas
+func renderWithBudget(cs *git.ChangeSet, budget int) (text string, truncated bool) {
+	full := RenderChanges(cs)
+	if cs.IsEmpty() || budget <= 0 || EstimateTokens(full) <= budget {
+		return full, false
+	}
+
+	files := make([]*budgetedFile, len(cs.Files))
+	for i, f := range cs.Files {
+		files[i] = &budgetedFile{file: f, hunks: f.Hunks}
+	}
+
+	summary := renderSummary(cs)
+	fits := func() bool {
+		total := EstimateTokens(summary)
+		for _, f := range files {
+			total += EstimateTokens(f.render())
+		}
+		return total <= budget
+	}
+
+	// 1. Trechos que alteram apenas espaços em branco
+	for _, f := range files {
+		var kept []git.Hunk
+		for _, h := range f.hunks {
+			if !isWhitespaceOnly(h) {
+				kept = append(kept, h)
+			}
+		}
+		if len(kept) < len(f.hunks) {
+			f.hunks = kept
+			f.omitted = "alterações apenas de espaços em branco"
+		}
+	}
+
+	// 2. Conteúdo de baixo valor: lockfiles, código gerado e dependências vendorizadas
+	if !fits() {
+		for _, f := range files {
+			if reason := lowValueReason(f.file); reason != "" && len(f.hunks) > 0 {
+				f.hunks = nil
+				f.omitted = reason
+			}
+		}
+	}
+
+	// 3. Divisão justa do orçamento restante entre os arquivos
+	if !fits() {
+		// Reserva espaço para o resumo dos arquivos omitidos (uma linha por arquivo)
+		omittedReserve := 16 + 24*len(files)
+		shareBudget(files, budget-EstimateTokens(summary)-omittedReserve)
+		truncated = true
+	}
+
+	var b strings.Builder
+	b.WriteString(summary)
+	b.WriteString("\nDiff completo:\n")
+	for _, f := range files {
+		if f.file.Binary || f.file.Withheld || len(f.hunks) > 0 {
+			b.WriteString(f.render())
+		}
+	}
+	b.WriteString(omittedSummary(files))
+	return b.String(), truncated
+}
+
+// shareBudget distribui o orçamento entre os arquivos: os menores recebem o que precisam
+// e a sobra é repartida igualmente entre os maiores, que são cortados em limites de trecho
+func shareBudget(files []*budgetedFile, budget int) {
+	order := make([]*budgetedFile, 0, len(files))
+	for _, f := range files {
+		if len(f.hunks) > 0 {
+			order = append(order, f)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return EstimateTokens(order[i].render()) < EstimateTokens(order[j].render())
+	})
+
+	remaining := budget
+	for i, f := range order {
+		share := remaining / (len(order) - i)
+		if share < 0 {
+			share = 0
+		}
+		if EstimateTokens(f.render()) > share {
+			truncateFile(f, share)
+		}
+		remaining -= EstimateTokens(f.render())
+	}
+}
+
+// truncateFile mantém os trechos iniciais do arquivo que cabem no orçamento.
+// Se nem o primeiro trecho couber, ele é cortado no limite de linha.
+func truncateFile(f *budgetedFile, budget int) {
+	header := *f
+	header.hunks = nil
+	used := EstimateTokens(header.render())
+
+	var kept []git.Hunk
+	for _, h := range f.hunks {
+		cost := EstimateTokens(renderHunk(h))
+		if used+cost <= budget {
+			kept = append(kept, h)
+			used += cost
+			continue
+		}
+		if len(kept) == 0 {
+			if partial, ok := truncateHunk(h, budget-used); ok {
+				kept = append(kept, partial)
+			}
+		}
+		break
+	}
+
+	omittedHunks := len(f.hunks) - len(kept)
+	f.hunks = kept
+	switch {
+	case len(kept) == 0:
+		f.omitted = "excede o orçamento de tokens"
+	case omittedHunks > 0:
+		f.omitted = fmt.Sprintf("%d trecho(s) omitido(s) por exceder o orçamento de tokens", omittedHunks)
+	default:
+		f.omitted = "trecho truncado por exceder o orçamento de tokens"
+	}
+}
+
+// truncateHunk mantém as primeiras linhas do trecho que cabem no orçamento,
+// descartando o excesso de contexto antes da primeira alteração
+func truncateHunk(h git.Hunk, budget int) (git.Hunk, bool) {
+	start := 0
+	for i, line := range h.Lines {
+		if len(line) > 0 && (line[0] == '+' || line[0] == '-') {
+			if i > 3 {
+				start = i - 3
+			}
+			break
+		}
+	}
+
+	used := EstimateTokens(h.Header + "\n")
+	var lines []string
+	for _, line := range h.Lines[start:] {
+		cost := EstimateTokens(line + "\n")
+		if used+cost > budget {
+			break
+		}
+		lines = append(lines, line)
+		used += cost
+	}
+	if len(lines) == 0 {
+		return h, false
+	}
+	h.Lines = append(lines, "\\ [trecho truncado]")
+	return h, true
+}
+
+// omittedSummary lista os arquivos cujo conteúdo foi omitido total ou parcialmente
+func omittedSummary(files []*budgetedFile) string {
+	var b strings.Builder
+	for _, f := range files {
+		if f.omitted == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString("\nConteúdo omitido para caber no limite do modelo:\n")
+		}
+		fmt.Fprintf(&b, "- %s (+%d -%d): %s\n", f.file.Path, f.file.Added, f.file.Deleted, f.omitted)
+	}
+	return b.String()
+}
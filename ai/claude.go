@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// ClaudeProvider implementa a interface Provider para a API da Anthropic (Claude)
+type ClaudeProvider struct {
+	ProviderOptions
+}
+
+// NewClaudeProvider cria uma nova instância de ClaudeProvider
+func NewClaudeProvider(opts ProviderOptions) *ClaudeProvider {
+	return &ClaudeProvider{ProviderOptions: opts}
+}
+
+// Estrutura para a requisição à API da Anthropic (Claude)
+type claudeRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature *float64  `json:"temperature,omitempty"`
+}
+
+type claudeResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// GenerateCommitMessage gera uma mensagem de commit com base nas mudanças usando Claude
+func (p *ClaudeProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	// Sem chave de API não há como chamar o provedor; o próximo da cadeia será usado
+	if p.APIKey == "" {
+		return "", fmt.Errorf("Claude: %w", ErrMissingAPIKey)
+	}
+
+	messages, err := buildMessages(req, p.promptBudget())
+	if err != nil {
+		return "", err
+	}
+
+	// A API da Anthropic recebe o prompt de sistema fora da lista de mensagens
+	reqBody := claudeRequest{
+		Model:       p.Model,
+		MaxTokens:   p.responseTokens(req),
+		Temperature: p.Temperature,
+	}
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			reqBody.System = msg.Content
+			continue
+		}
+		reqBody.Messages = append(reqBody.Messages, msg)
+	}
+
+	headers := map[string]string{
+		"x-api-key":         p.APIKey,
+		"anthropic-version": "2023-06-01",
+	}
+	for key, value := range p.Headers {
+		headers[key] = value
+	}
+
+	var resp claudeResponse
+	if err := postJSON(ctx, "Claude", p.endpoint("/messages"), headers, reqBody, &resp); err != nil {
+		return "", err
+	}
+
+	if len(resp.Content) == 0 {
+		return "", fmt.Errorf("nenhuma resposta gerada pela IA")
+	}
+
+	return resp.Content[0].Text, nil
+}
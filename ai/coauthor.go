@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// DefaultRosterFile é o arquivo de equipe padrão, relativo à raiz do repositório
+const DefaultRosterFile = ".commit-ai/coauthors"
+
+// coauthorHistoryDepth é a quantidade de commits recentes de onde os coautores são sugeridos
+const coauthorHistoryDepth = 100
+
+// CommitOptionsFor monta as opções de commit da configuração: autor, coautores (resolvidos
+// pela equipe e pelos autores recentes) e Signed-off-by
+func CommitOptionsFor(repo *git.Repository, cfg *config.Config) (git.CommitOptions, error) {
+	opts := git.CommitOptions{SignOff: cfg.SignOff}
+	if cfg.Author != "" {
+		author, err := git.ParsePerson(cfg.Author)
+		if err != nil {
+			return opts, err
+		}
+		opts.Author = &author
+	}
+	if len(cfg.CoAuthors) == 0 {
+		return opts, nil
+	}
+
+	candidates, err := CoauthorCandidates(repo, cfg)
+	if err != nil {
+		return opts, err
+	}
+	for _, query := range cfg.CoAuthors {
+		p, err := git.FindPerson(query, candidates)
+		if err != nil {
+			return opts, err
+		}
+		opts.CoAuthors = append(opts.CoAuthors, p)
+	}
+	return opts, nil
+}
+
+// CoauthorCandidates retorna os possíveis coautores: primeiro a equipe do arquivo
+// co_author_roster, depois os autores dos commits recentes, sem repetições
+func CoauthorCandidates(repo *git.Repository, cfg *config.Config) ([]git.Person, error) {
+	file := cfg.CoAuthorRoster
+	if file == "" {
+		file = DefaultRosterFile
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(repo.Path, file)
+	}
+	roster, err := git.LoadRoster(file)
+	if err != nil {
+		return nil, err
+	}
+	recent, err := repo.RecentAuthors(coauthorHistoryDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []git.Person
+	seen := make(map[string]bool)
+	for _, p := range append(roster, recent...) {
+		email := strings.ToLower(p.Email)
+		if !seen[email] {
+			seen[email] = true
+			candidates = append(candidates, p)
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,329 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// DefaultRepairAttempts é o número padrão de novas solicitações ao provedor quando a
+// mensagem gerada não segue o formato esperado
+const DefaultRepairAttempts = 2
+
+// maxSubjectLength é o tamanho máximo da linha de assunto pedido nos prompts
+const maxSubjectLength = 100
+
+// ConventionalTypes são os tipos aceitos na linha de assunto
+var ConventionalTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// Footer representa um rodapé da mensagem (ex.: "Refs: #123" ou "BREAKING CHANGE: ...")
+type Footer struct {
+	Token string
+	Value string
+}
+
+// CommitMessage é uma mensagem de commit decomposta segundo o Conventional Commits
+type CommitMessage struct {
+	Type        string   // Tipo da alteração (feat, fix, ...)
+	Scope       string   // Escopo opcional
+	Breaking    bool     // Indica "!" antes dos dois-pontos
+	Description string   // Descrição curta da linha de assunto
+	Body        string   // Corpo, sem os rodapés
+	Footers     []Footer // Rodapés no final da mensagem
+}
+
+// headerPattern reconhece a linha de assunto "tipo(escopo)!: descrição"
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// footerSeparator separa o token do valor em um rodapé
+var footerSeparator = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][A-Za-z0-9-]*)(: | #)(.*)$`)
+
+// ParseConventional decompõe uma mensagem no formato Conventional Commits.
+// Retorna erro apenas quando a linha de assunto não segue o formato "tipo(escopo)!: descrição";
+// os demais problemas são apontados por ValidateConventional.
+func ParseConventional(msg string) (*CommitMessage, error) {
+	msg = strings.TrimSpace(strings.ReplaceAll(msg, "\r\n", "\n"))
+	lines := strings.Split(msg, "\n")
+
+	m := headerPattern.FindStringSubmatch(lines[0])
+	if m == nil {
+		return nil, fmt.Errorf("linha de assunto fora do formato \"tipo(escopo): descrição\": %q", lines[0])
+	}
+	cm := &CommitMessage{
+		Type:        m[1],
+		Scope:       m[2],
+		Breaking:    m[3] == "!",
+		Description: m[4],
+	}
+
+	rest := strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	if rest == "" {
+		return cm, nil
+	}
+
+	// O último parágrafo é de rodapés se todas as suas linhas forem rodapés ou continuações
+	paragraphs := strings.Split(rest, "\n\n")
+	last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	if isFooterBlock(last) {
+		for _, line := range last {
+			if f := footerSeparator.FindStringSubmatch(line); f != nil {
+				token := f[1]
+				if f[2] == " #" {
+					f[3] = "#" + f[3]
+				}
+				cm.Footers = append(cm.Footers, Footer{Token: token, Value: f[3]})
+			} else if len(cm.Footers) > 0 {
+				cm.Footers[len(cm.Footers)-1].Value += "\n" + line
+			}
+		}
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+	cm.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+	return cm, nil
+}
+
+// Header retorna a linha de assunto da mensagem
+func (m *CommitMessage) Header() string {
+	header := m.Type
+	if m.Scope != "" {
+		header += "(" + m.Scope + ")"
+	}
+	if m.Breaking {
+		header += "!"
+	}
+	return header + ": " + m.Description
+}
+
+// IsBreaking indica se a mensagem declara uma mudança incompatível ("!" ou rodapé BREAKING CHANGE)
+func (m *CommitMessage) IsBreaking() bool {
+	if m.Breaking {
+		return true
+	}
+	for _, f := range m.Footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			return true
+		}
+	}
+	return false
+}
+
+// String remonta a mensagem completa: assunto, corpo e rodapés separados por linhas em branco
+func (m *CommitMessage) String() string {
+	parts := []string{m.Header()}
+	if m.Body != "" {
+		parts = append(parts, m.Body)
+	}
+	if len(m.Footers) > 0 {
+		footers := make([]string, len(m.Footers))
+		for i, f := range m.Footers {
+			if strings.HasPrefix(f.Value, "#") {
+				footers[i] = f.Token + " " + f.Value
+			} else {
+				footers[i] = f.Token + ": " + f.Value
+			}
+		}
+		parts = append(parts, strings.Join(footers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ValidateConventional verifica a mensagem contra a gramática do Conventional Commits
+// e retorna a lista de problemas encontrados (vazia quando a mensagem é válida)
+func ValidateConventional(msg string) []string {
+	msg = strings.TrimSpace(strings.ReplaceAll(msg, "\r\n", "\n"))
+	if msg == "" {
+		return []string{"a mensagem está vazia"}
+	}
+
+	cm, err := ParseConventional(msg)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	if !isConventionalType(cm.Type) {
+		problems = append(problems, fmt.Sprintf("tipo %q inválido; use um de: %s", cm.Type, strings.Join(ConventionalTypes, ", ")))
+	} else if cm.Type != strings.ToLower(cm.Type) {
+		problems = append(problems, fmt.Sprintf("o tipo %q deve estar em minúsculas", cm.Type))
+	}
+	if strings.HasPrefix(msg, cm.Type+"()") {
+		problems = append(problems, "o escopo entre parênteses não pode estar vazio")
+	}
+	if strings.ContainsAny(cm.Scope, " \t") {
+		problems = append(problems, fmt.Sprintf("o escopo %q não pode conter espaços", cm.Scope))
+	}
+	if strings.TrimSpace(cm.Description) == "" {
+		problems = append(problems, "a descrição após \"tipo: \" está vazia")
+	} else if cm.Description != strings.TrimSpace(cm.Description) {
+		problems = append(problems, "a descrição não pode começar ou terminar com espaços")
+	}
+
+	lines := strings.Split(msg, "\n")
+	if n := len([]rune(lines[0])); n > maxSubjectLength {
+		problems = append(problems, fmt.Sprintf("a linha de assunto tem %d caracteres; o máximo é %d", n, maxSubjectLength))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "deve haver uma linha em branco entre o assunto e o corpo")
+	}
+	for _, f := range cm.Footers {
+		if strings.EqualFold(f.Token, "breaking change") && f.Token != "BREAKING CHANGE" {
+			problems = append(problems, "o rodapé de mudança incompatível deve ser escrito \"BREAKING CHANGE\"")
+		}
+		if strings.TrimSpace(f.Value) == "" {
+			problems = append(problems, fmt.Sprintf("o rodapé %q está vazio", f.Token))
+		}
+	}
+	return problems
+}
+
+// isConventionalType indica se o tipo está entre os aceitos (sem diferenciar maiúsculas)
+func isConventionalType(kind string) bool {
+	for _, t := range ConventionalTypes {
+		if strings.EqualFold(t, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// preamblePattern reconhece rótulos que os modelos costumam colocar antes da mensagem
+var preamblePattern = regexp.MustCompile(`(?i)^\s*(?:\*\*)?(?:commit message|suggested commit message|mensagem de commit|mensagem|mensaje de commit|message de commit|commit-nachricht|message)(?:\*\*)?\s*:\s*(?:\*\*)?\s*`)
+
+// CleanMessage remove os invólucros que os modelos costumam adicionar à mensagem:
+// blocos de código, aspas, negrito e frases introdutórias como "Here is your commit message:"
+func CleanMessage(raw string) string {
+	msg := strings.TrimSpace(strings.ReplaceAll(raw, "\r\n", "\n"))
+
+	// Preferir o conteúdo do primeiro bloco de código, se houver
+	if start := strings.Index(msg, "```"); start >= 0 {
+		inner := msg[start+3:]
+		if nl := strings.IndexByte(inner, '\n'); nl >= 0 {
+			inner = inner[nl+1:] // Descarta a linguagem do bloco (ex.: ```text)
+		}
+		if end := strings.Index(inner, "```"); end >= 0 {
+			inner = inner[:end]
+		}
+		if strings.TrimSpace(inner) != "" {
+			msg = strings.TrimSpace(inner)
+		}
+	}
+
+	// Descartar linhas introdutórias antes da linha de assunto
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		if i >= 3 {
+			break
+		}
+		if headerPattern.MatchString(unwrapLine(preamblePattern.ReplaceAllString(line, ""))) {
+			lines = lines[i:]
+			break
+		}
+	}
+	for len(lines) > 1 && (strings.TrimSpace(lines[0]) == "" ||
+		strings.HasSuffix(strings.TrimSpace(lines[0]), ":") && !headerPattern.MatchString(lines[0])) {
+		lines = lines[1:]
+	}
+	lines[0] = unwrapLine(preamblePattern.ReplaceAllString(lines[0], ""))
+	msg = strings.TrimSpace(strings.Join(lines, "\n"))
+
+	return unwrapLine(msg)
+}
+
+// unwrapLine remove aspas, crases e marcadores de negrito que envolvem o texto inteiro
+func unwrapLine(s string) string {
+	s = strings.TrimSpace(s)
+	for _, pair := range [][2]string{{"**", "**"}, {"\"", "\""}, {"'", "'"}, {"`", "`"}, {"“", "”"}} {
+		if len(s) > len(pair[0])+len(pair[1]) && strings.HasPrefix(s, pair[0]) && strings.HasSuffix(s, pair[1]) {
+			s = strings.TrimSpace(s[len(pair[0]) : len(s)-len(pair[1])])
+		}
+	}
+	return s
+}
+
+// validatingProvider limpa a mensagem gerada e, se ela não for válida, pede ao provedor
+// que a corrija, informando os problemas encontrados
+type validatingProvider struct {
+	provider Provider
+	attempts int
+}
+
+// WithValidation envolve um provedor para limpar e validar as mensagens geradas com o
+// validador do estilo da requisição e com os escopos permitidos, impondo o escopo deduzido e
+// a marcação de mudanças incompatíveis quando solicitados. Mensagens inválidas são
+// reenviadas ao provedor com a lista de problemas até attempts vezes; se ainda forem
+// inválidas, a última mensagem é retornada e os problemas são registrados no log.
+func WithValidation(provider Provider, attempts int) Provider {
+	if attempts < 0 {
+		attempts = 0
+	}
+	return &validatingProvider{provider: provider, attempts: attempts}
+}
+
+// GenerateCommitMessage gera, limpa e valida a mensagem, pedindo correções quando necessário
+func (p *validatingProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	if req.Task != TaskCommitMessage {
+		return p.provider.GenerateCommitMessage(ctx, req)
+	}
+
+	validate := req.style().Validate
+	var msg string
+	var problems []string
+	for attempt := 0; attempt <= p.attempts; attempt++ {
+		raw, err := p.provider.GenerateCommitMessage(ctx, req)
+		if err != nil {
+			if attempt > 0 && msg != "" && ctx.Err() == nil {
+				log.Printf("Erro ao corrigir mensagem de commit: %v; usando a versão anterior", err)
+				break
+			}
+			return "", err
+		}
+
+		msg = CleanMessage(raw)
+		if req.EnforceScope {
+			msg = replaceScope(msg, req.Scope)
+		}
+		if len(req.Breaking) > 0 {
+			msg = markBreaking(msg, req.Breaking, req.Language)
+		}
+		if validate == nil {
+			return msg, nil
+		}
+		problems = validate(msg)
+		if !req.EnforceScope {
+			problems = append(problems, checkScope(msg, req.Scopes)...)
+		}
+		if len(problems) == 0 {
+			return msg, nil
+		}
+		if attempt < p.attempts {
+			log.Printf("Mensagem gerada inválida (%s); solicitando correção (%d/%d)", strings.Join(problems, "; "), attempt+1, p.attempts)
+		}
+		req.Previous = msg
+		req.Problems = problems
+	}
+
+	if msg == "" {
+		return "", fmt.Errorf("mensagem de commit inválida: %s", strings.Join(problems, "; "))
+	}
+	log.Printf("Mensagem de commit ainda inválida após %d correção(ões): %s", p.attempts, strings.Join(problems, "; "))
+	return msg, nil
+}
+
+// getRepairInstructions retorna o pedido de correção de uma mensagem inválida no idioma solicitado
+func getRepairInstructions(previous string, problems []string, language string) string {
+	list := "- " + strings.Join(problems, "\n- ")
+	switch language {
+	case "en":
+		return fmt.Sprintf("\n\nYour previous answer was rejected:\n\n%s\n\nProblems found:\n%s\n\nFix these problems and respond only with the corrected commit message.", previous, list)
+	case "es":
+		return fmt.Sprintf("\n\nTu respuesta anterior fue rechazada:\n\n%s\n\nProblemas encontrados:\n%s\n\nCorrige estos problemas y responde solamente con el mensaje de commit corregido.", previous, list)
+	case "fr":
+		return fmt.Sprintf("\n\nVotre réponse précédente a été rejetée:\n\n%s\n\nProblèmes trouvés:\n%s\n\nCorrigez ces problèmes et répondez uniquement avec le message de commit corrigé.", previous, list)
+	case "de":
+		return fmt.Sprintf("\n\nIhre vorherige Antwort wurde abgelehnt:\n\n%s\n\nGefundene Probleme:\n%s\n\nBeheben Sie diese Probleme und antworten Sie nur mit der korrigierten Commit-Nachricht.", previous, list)
+	default: // Padrão é português pt-br
+		return fmt.Sprintf("\n\nSua resposta anterior foi rejeitada:\n\n%s\n\nProblemas encontrados:\n%s\n\nCorrija esses problemas e responda apenas com a mensagem de commit corrigida.", previous, list)
+	}
+}
@@ -0,0 +1,306 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/user/commit-ai/git"
+)
+
+// HeuristicProvider implementa a interface Provider sem usar um modelo de linguagem.
+// A mensagem é deduzida apenas do diff: o tipo vem dos caminhos alterados, o escopo
+// do diretório comum e a descrição dos símbolos adicionados ou removidos.
+// Funciona sem rede e é usado como último fallback da cadeia de provedores.
+type HeuristicProvider struct{}
+
+// NewHeuristicProvider cria uma nova instância de HeuristicProvider
+func NewHeuristicProvider() *HeuristicProvider {
+	return &HeuristicProvider{}
+}
+
+// maxHeuristicSubject é o tamanho máximo da linha de assunto gerada
+const maxHeuristicSubject = 72
+
+// symbolPatterns reconhecem declarações de funções, tipos e classes em linguagens comuns
+var symbolPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^func\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)`),                                        // Go
+	regexp.MustCompile(`^type\s+([A-Za-z_]\w*)\s`),                                                       // Go
+	regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)`), // JavaScript/TypeScript
+	regexp.MustCompile(`^(?:export\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)`),                      // JavaScript/TypeScript/Python
+	regexp.MustCompile(`^(?:export\s+)?interface\s+([A-Za-z_$][\w$]*)`),                                  // TypeScript
+	regexp.MustCompile(`^(?:async\s+)?def\s+([A-Za-z_]\w*)`),                                             // Python
+	regexp.MustCompile(`^(?:pub(?:\([^)]*\))?\s+)?(?:fn|struct|enum|trait)\s+([A-Za-z_]\w*)`),            // Rust
+}
+
+// extractSymbol retorna o nome do símbolo declarado na linha, se houver
+func extractSymbol(line string) string {
+	line = strings.TrimSpace(line)
+	for _, pattern := range symbolPatterns {
+		if m := pattern.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// fileSymbols retorna os símbolos declarados nas linhas adicionadas e removidas do arquivo
+func fileSymbols(f git.FileChange) (added []string, removed []string) {
+	for _, h := range f.Hunks {
+		for _, line := range h.Lines {
+			if len(line) == 0 {
+				continue
+			}
+			symbol := extractSymbol(line[1:])
+			if symbol == "" {
+				continue
+			}
+			switch line[0] {
+			case '+':
+				added = append(added, symbol)
+			case '-':
+				removed = append(removed, symbol)
+			}
+		}
+	}
+	return added, removed
+}
+
+// inferFileType deduz o tipo de Conventional Commit a partir do caminho do arquivo.
+// Retorna uma string vazia para arquivos de código comuns.
+func inferFileType(file string) string {
+	base := path.Base(file)
+	lower := strings.ToLower(file)
+
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."), strings.Contains(base, ".spec."),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasPrefix(lower, "test/"), strings.HasPrefix(lower, "tests/"),
+		strings.Contains(lower, "/test/"), strings.Contains(lower, "/tests/"),
+		strings.Contains(lower, "__tests__/"):
+		return "test"
+	case strings.HasPrefix(lower, ".github/workflows/"), strings.HasPrefix(lower, ".circleci/"),
+		base == ".gitlab-ci.yml", base == ".travis.yml", base == "Jenkinsfile", base == "azure-pipelines.yml":
+		return "ci"
+	case base == "go.mod", base == "go.sum", base == "package.json", base == "package-lock.json",
+		base == "yarn.lock", base == "pnpm-lock.yaml", base == "Cargo.toml", base == "Cargo.lock",
+		base == "pyproject.toml", base == "requirements.txt", base == "Makefile", base == "Dockerfile",
+		strings.HasSuffix(base, ".mk"):
+		return "build"
+	case strings.HasSuffix(lower, ".md"), strings.HasSuffix(lower, ".rst"), strings.HasSuffix(lower, ".adoc"),
+		strings.HasSuffix(lower, ".txt") && !strings.HasPrefix(base, "requirements"),
+		strings.HasPrefix(lower, "docs/"), base == "LICENSE":
+		return "docs"
+	}
+	return ""
+}
+
+// inferCommitType deduz o tipo da mensagem a partir do conjunto de arquivos
+func inferCommitType(files []git.FileChange) string {
+	kinds := make(map[string]int)
+	var code []git.FileChange
+	for _, f := range files {
+		kind := inferFileType(f.Path)
+		if kind == "" {
+			code = append(code, f)
+			continue
+		}
+		kinds[kind]++
+	}
+
+	// Sem código de produção alterado, o tipo mais frequente entre os arquivos auxiliares vence
+	if len(code) == 0 {
+		best, bestCount := "chore", 0
+		for _, kind := range []string{"test", "docs", "build", "ci"} {
+			if kinds[kind] > bestCount {
+				best, bestCount = kind, kinds[kind]
+			}
+		}
+		return best
+	}
+
+	added, removed := symbolChanges(code)
+	for _, f := range code {
+		if f.Status == git.StatusAdded {
+			return "feat"
+		}
+	}
+	switch {
+	case len(added) > 0:
+		return "feat"
+	case len(removed) > 0:
+		return "refactor"
+	default:
+		return "fix"
+	}
+}
+
+// symbolChanges retorna os símbolos adicionados e removidos (um símbolo presente nos
+// dois lados foi apenas modificado e não entra em nenhuma das listas)
+func symbolChanges(files []git.FileChange) (added []string, removed []string) {
+	addedSet := make(map[string]bool)
+	removedSet := make(map[string]bool)
+	for _, f := range files {
+		fileAdded, fileRemoved := fileSymbols(f)
+		for _, s := range fileAdded {
+			addedSet[s] = true
+		}
+		for _, s := range fileRemoved {
+			removedSet[s] = true
+		}
+	}
+	for s := range addedSet {
+		if !removedSet[s] {
+			added = append(added, s)
+		}
+	}
+	for s := range removedSet {
+		if !addedSet[s] {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// heuristicPhrases contém os modelos de frase usados na descrição, por idioma
+var heuristicPhrases = map[string]map[string]string{
+	"en":    {"add": "add %s", "remove": "remove %s", "update": "update %s", "rename": "rename %s to %s", "and": "and", "more": "%d more", "files": "%d files"},
+	"es":    {"add": "agrega %s", "remove": "elimina %s", "update": "actualiza %s", "rename": "renombra %s a %s", "and": "y", "more": "%d más", "files": "%d archivos"},
+	"fr":    {"add": "ajoute %s", "remove": "supprime %s", "update": "met à jour %s", "rename": "renomme %s en %s", "and": "et", "more": "%d autres", "files": "%d fichiers"},
+	"de":    {"add": "füge %s hinzu", "remove": "entferne %s", "update": "aktualisiere %s", "rename": "benenne %s in %s um", "and": "und", "more": "%d weitere", "files": "%d Dateien"},
+	"pt-br": {"add": "adiciona %s", "remove": "remove %s", "update": "atualiza %s", "rename": "renomeia %s para %s", "and": "e", "more": "mais %d", "files": "%d arquivos"},
+}
+
+// phrasesFor retorna os modelos de frase do idioma informado (português por padrão)
+func phrasesFor(language string) map[string]string {
+	if phrases, ok := heuristicPhrases[language]; ok {
+		return phrases
+	}
+	return heuristicPhrases["pt-br"]
+}
+
+// joinNames lista até três nomes e resume o restante
+func joinNames(names []string, phrases map[string]string) string {
+	switch {
+	case len(names) == 1:
+		return names[0]
+	case len(names) <= 3:
+		return strings.Join(names[:len(names)-1], ", ") + " " + phrases["and"] + " " + names[len(names)-1]
+	default:
+		return strings.Join(names[:3], ", ") + " " + phrases["and"] + " " + fmt.Sprintf(phrases["more"], len(names)-3)
+	}
+}
+
+// describeChanges monta a descrição da mensagem a partir dos símbolos e arquivos alterados
+func describeChanges(files []git.FileChange, language string) string {
+	phrases := phrasesFor(language)
+	added, removed := symbolChanges(files)
+
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, fmt.Sprintf(phrases["add"], joinNames(added, phrases)))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, fmt.Sprintf(phrases["remove"], joinNames(removed, phrases)))
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, "; ")
+	}
+
+	if len(files) == 1 {
+		f := files[0]
+		switch f.Status {
+		case git.StatusAdded:
+			return fmt.Sprintf(phrases["add"], path.Base(f.Path))
+		case git.StatusDeleted:
+			return fmt.Sprintf(phrases["remove"], path.Base(f.Path))
+		case git.StatusRenamed:
+			return fmt.Sprintf(phrases["rename"], path.Base(f.OldPath), path.Base(f.Path))
+		default:
+			return fmt.Sprintf(phrases["update"], path.Base(f.Path))
+		}
+	}
+
+	var names []string
+	for _, f := range files {
+		names = append(names, path.Base(f.Path))
+	}
+	if len(names) <= 3 {
+		return fmt.Sprintf(phrases["update"], joinNames(names, phrases))
+	}
+	return fmt.Sprintf(phrases["update"], fmt.Sprintf(phrases["files"], len(names)))
+}
+
+// GenerateCommitMessage gera uma mensagem de commit a partir do diff, sem chamadas de rede
+func (p *HeuristicProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if req.Task == TaskSplit {
+		return "", fmt.Errorf("o provedor heurístico não planeja a divisão de commits")
+	}
+	if req.ChangeSet.IsEmpty() {
+		return "", fmt.Errorf("nenhuma alteração encontrada para gerar a mensagem")
+	}
+	if req.Task == TaskPullRequest {
+		req.Task = TaskCommitMessage
+		title, err := p.GenerateCommitMessage(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return heuristicPullRequest(title, req), nil
+	}
+	files := req.ChangeSet.Files
+
+	// O escopo é o deduzido por ScopeFor, o mesmo sugerido aos demais provedores
+	subject := formatHeuristicSubject(req.style().Name, inferCommitType(files), req.Scope,
+		describeChanges(files, req.Language), files)
+
+	return truncateSubject(subject, maxHeuristicSubject), nil
+}
+
+// formatHeuristicSubject monta a linha de assunto no estilo solicitado
+func formatHeuristicSubject(style string, kind string, scope string, description string, files []git.FileChange) string {
+	switch style {
+	case "gitmoji":
+		return gitmojis[kind] + " " + description
+	case "kernel":
+		if scope == "" {
+			scope = strings.TrimSuffix(path.Base(files[0].Path), path.Ext(files[0].Path))
+		}
+		return scope + ": " + description
+	case "plain":
+		runes := []rune(description)
+		return strings.ToUpper(string(runes[0])) + string(runes[1:])
+	case "angular":
+		if kind == "chore" {
+			kind = "build"
+		}
+	}
+
+	subject := kind
+	if scope != "" {
+		subject += "(" + scope + ")"
+	}
+	return subject + ": " + description
+}
+
+// truncateSubject limita o assunto ao tamanho máximo, cortando no último espaço possível
+func truncateSubject(subject string, limit int) string {
+	runes := []rune(subject)
+	if len(runes) <= limit {
+		return subject
+	}
+	cut := string(runes[:limit-3])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;") + "..."
+}
@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+const (
+	// ScopeHistoryDepth é o número de commits analisados para inferir os escopos permitidos
+	ScopeHistoryDepth = 200
+
+	// minScopeOccurrences é o número mínimo de commits em que um escopo deve aparecer para ser
+	// considerado uma convenção do repositório
+	minScopeOccurrences = 2
+)
+
+// LoadHistory lê do branch atual as mensagens usadas como exemplo no prompt e, quando
+// infer_scopes está ativo, os escopos permitidos, inferidos de um histórico mais longo
+func LoadHistory(repo *git.Repository, cfg *config.Config) (examples []string, scopes []string, err error) {
+	samples := cfg.HistorySampleCount()
+	depth := samples
+	if cfg.InferScopes && depth < ScopeHistoryDepth {
+		depth = ScopeHistoryDepth
+	}
+
+	messages, err := repo.RecentCommitMessages(depth)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.InferScopes {
+		scopes = InferScopes(messages)
+	}
+	if len(messages) > samples {
+		messages = messages[:samples]
+	}
+	return messages, scopes, nil
+}
+
+// InferScopes retorna os escopos usados com frequência nas mensagens de commit informadas,
+// do mais frequente para o menos frequente. Escopos que aparecem uma única vez são ignorados.
+func InferScopes(messages []string) []string {
+	counts := make(map[string]int)
+	for _, msg := range messages {
+		parsed, err := ParseConventional(msg)
+		if err != nil || parsed.Scope == "" {
+			continue
+		}
+		counts[strings.ToLower(parsed.Scope)]++
+	}
+
+	var scopes []string
+	for scope, count := range counts {
+		if count >= minScopeOccurrences {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if counts[scopes[i]] != counts[scopes[j]] {
+			return counts[scopes[i]] > counts[scopes[j]]
+		}
+		return scopes[i] < scopes[j]
+	})
+	return scopes
+}
+
+// checkScope verifica se o escopo da mensagem está entre os permitidos.
+// Mensagens sem escopo ou fora do formato Conventional Commits não são verificadas.
+func checkScope(msg string, scopes []string) []string {
+	if len(scopes) == 0 {
+		return nil
+	}
+	parsed, err := ParseConventional(msg)
+	if err != nil || parsed.Scope == "" {
+		return nil
+	}
+	for _, scope := range scopes {
+		if strings.EqualFold(parsed.Scope, scope) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("o escopo %q não é usado neste repositório; use um destes: %s", parsed.Scope, strings.Join(scopes, ", "))}
+}
+
+// fewShotExamples formata as mensagens do histórico como exemplos para o prompt.
+// Sem corpo, apenas a linha de assunto de cada mensagem é usada.
+func fewShotExamples(messages []string, body bool) string {
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		msg = strings.TrimSpace(msg)
+		if !body {
+			msg, _, _ = strings.Cut(msg, "\n")
+			msg = strings.TrimSpace(msg)
+		}
+		if msg == "" || seen[msg] {
+			continue
+		}
+		seen[msg] = true
+
+		if body {
+			fmt.Fprintf(&b, "\n---\n%s\n", msg)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", msg)
+		}
+	}
+	if body && b.Len() > 0 {
+		b.WriteString("---\n")
+	}
+	return b.String()
+}
+
+// getHistoryInstructions retorna, no idioma solicitado, os exemplos do histórico do
+// repositório e a lista de escopos permitidos
+func getHistoryInstructions(messages []string, scopes []string, body bool, language string) string {
+	var b strings.Builder
+	if examples := fewShotExamples(messages, body); examples != "" {
+		switch language {
+		case "en":
+			b.WriteString("\n\nRecent commit messages from this repository. Follow their conventions for scopes, casing, wording and ticket references, but describe only the changes above:\n")
+		case "es":
+			b.WriteString("\n\nMensajes de commit recientes de este repositorio. Sigue sus convenciones de alcances, mayúsculas, redacción y referencias a tickets, pero describe solo los cambios anteriores:\n")
+		case "fr":
+			b.WriteString("\n\nMessages de commit récents de ce dépôt. Suivez leurs conventions de portées, de casse, de formulation et de références aux tickets, mais décrivez uniquement les modifications ci-dessus:\n")
+		case "de":
+			b.WriteString("\n\nAktuelle Commit-Nachrichten aus diesem Repository. Folgen Sie ihren Konventionen für Bereiche, Groß- und Kleinschreibung, Formulierung und Ticket-Referenzen, beschreiben Sie aber nur die obigen Änderungen:\n")
+		default: // Padrão é português pt-br
+			b.WriteString("\n\nMensagens de commit recentes deste repositório. Siga as convenções delas para escopos, maiúsculas, redação e referências a tickets, mas descreva apenas as mudanças acima:\n")
+		}
+		b.WriteString(strings.TrimRight(examples, "\n"))
+	}
+
+	if len(scopes) > 0 {
+		list := strings.Join(scopes, ", ")
+		switch language {
+		case "en":
+			fmt.Fprintf(&b, "\n\nIf you use a scope, it must be one of: %s", list)
+		case "es":
+			fmt.Fprintf(&b, "\n\nSi usas un alcance, debe ser uno de: %s", list)
+		case "fr":
+			fmt.Fprintf(&b, "\n\nSi vous utilisez une portée, elle doit être l'une de: %s", list)
+		case "de":
+			fmt.Fprintf(&b, "\n\nWenn Sie einen Bereich verwenden, muss er einer der folgenden sein: %s", list)
+		default: // Padrão é português pt-br
+			fmt.Fprintf(&b, "\n\nSe usar um escopo, ele deve ser um destes: %s", list)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// BodyWidth é a largura máxima das linhas do corpo da mensagem de commit
+	BodyWidth = 72
+
+	// bodyMaxTokens é o limite mínimo de tokens da resposta quando o corpo é solicitado
+	bodyMaxTokens = 500
+)
+
+// footerPattern reconhece rodapés no formato de trailers do Git ("Token: valor" ou "Token #valor")
+var footerPattern = regexp.MustCompile(`^(?:BREAKING[ -]CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | #)`)
+
+// listItemPattern reconhece itens de lista no corpo ("- ", "* " ou "1. ")
+var listItemPattern = regexp.MustCompile(`^(?:[-*]|\d+[.)])\s+`)
+
+// WrapMessage normaliza uma mensagem de commit com corpo: mantém o assunto na primeira linha,
+// separa assunto, parágrafos e rodapés por uma linha em branco e quebra os parágrafos do corpo
+// na largura informada. Itens de lista e rodapés recebem recuo nas linhas de continuação;
+// palavras que não podem ser quebradas (como URLs longas) são mantidas inteiras.
+func WrapMessage(msg string, width int) string {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(msg, "\r\n", "\n")), "\n")
+	subject := strings.TrimSpace(lines[0])
+
+	// Agrupar as linhas restantes em parágrafos separados por linhas em branco
+	var paragraphs [][]string
+	var current []string
+	for _, line := range lines[1:] {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+
+	out := []string{subject}
+	for _, paragraph := range paragraphs {
+		out = append(out, "")
+		if isFooterBlock(paragraph) {
+			for _, line := range paragraph {
+				wrapped := wrapText(line, width, " ")
+				if strings.HasPrefix(line, " ") {
+					wrapped[0] = " " + wrapped[0]
+				}
+				out = append(out, wrapped...)
+			}
+			continue
+		}
+		out = append(out, wrapParagraph(paragraph, width)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// isFooterBlock indica se todas as linhas do parágrafo são rodapés (ou continuações recuadas)
+func isFooterBlock(lines []string) bool {
+	if !footerPattern.MatchString(lines[0]) {
+		return false
+	}
+	for _, line := range lines[1:] {
+		if !footerPattern.MatchString(line) && !strings.HasPrefix(line, " ") {
+			return false
+		}
+	}
+	return true
+}
+
+// appendFooter adiciona um rodapé à mensagem, junto aos rodapés existentes quando houver
+func appendFooter(msg string, footer string) string {
+	paragraphs := strings.Split(msg, "\n\n")
+	last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	if len(paragraphs) > 1 && isFooterBlock(last) {
+		return msg + "\n" + footer
+	}
+	return msg + "\n\n" + footer
+}
+
+// wrapParagraph quebra um parágrafo na largura informada, tratando cada item de lista separadamente
+func wrapParagraph(lines []string, width int) []string {
+	// Reunir as linhas em blocos: um bloco por item de lista, ou um único bloco de texto corrido
+	var blocks []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if listItemPattern.MatchString(trimmed) || len(blocks) == 0 {
+			blocks = append(blocks, trimmed)
+			continue
+		}
+		blocks[len(blocks)-1] += " " + trimmed
+	}
+
+	var out []string
+	for _, block := range blocks {
+		indent := ""
+		if m := listItemPattern.FindString(block); m != "" {
+			indent = strings.Repeat(" ", len(m))
+		}
+		out = append(out, wrapText(block, width, indent)...)
+	}
+	return out
+}
+
+// wrapText quebra o texto em linhas de até width caracteres, usando indent nas continuações.
+// Palavras maiores que a largura ficam sozinhas na linha.
+func wrapText(text string, width int, indent string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = indent + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// formattingProvider ajusta o formato da mensagem gerada por outro provedor
+type formattingProvider struct {
+	provider Provider
+}
+
+// GenerateCommitMessage delega a geração, cita os tickets do branch e quebra o corpo da
+// mensagem quando ele foi solicitado
+func (p *formattingProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	msg, err := p.provider.GenerateCommitMessage(ctx, req)
+	if err != nil || req.Task != TaskCommitMessage {
+		return msg, err
+	}
+	if len(req.Tickets) > 0 {
+		msg = applyTickets(msg, req.Tickets, req.TicketPlacement)
+	}
+	if !req.Body {
+		return msg, nil
+	}
+	return WrapMessage(msg, BodyWidth), nil
+}
+
+// getBodyInstructions retorna as instruções adicionais para gerar corpo e rodapés no idioma solicitado
+func getBodyInstructions(language string) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf(`
+
+Also write a body and, when applicable, footers:
+- After the subject line, leave a blank line and write one or more paragraphs explaining why the change was made and what it affects, not just what changed
+- Wrap the body at %d characters per line
+- After the body, leave a blank line and add footers when applicable: "BREAKING CHANGE: <description>" for incompatible changes and "Refs: <reference>" for related issues
+- Do not invent issue numbers`, BodyWidth)
+	case "es":
+		return fmt.Sprintf(`
+
+Escribe también un cuerpo y, cuando corresponda, pies:
+- Después de la línea de asunto, deja una línea en blanco y escribe uno o más párrafos explicando por qué se hizo el cambio y qué afecta, no solo qué cambió
+- Ajusta el cuerpo a %d caracteres por línea
+- Después del cuerpo, deja una línea en blanco y agrega pies cuando corresponda: "BREAKING CHANGE: <descripción>" para cambios incompatibles y "Refs: <referencia>" para issues relacionados
+- No inventes números de issues`, BodyWidth)
+	case "fr":
+		return fmt.Sprintf(`
+
+Écrivez aussi un corps et, le cas échéant, des pieds de page:
+- Après la ligne de sujet, laissez une ligne vide et écrivez un ou plusieurs paragraphes expliquant pourquoi la modification a été faite et ce qu'elle affecte, pas seulement ce qui a changé
+- Limitez le corps à %d caractères par ligne
+- Après le corps, laissez une ligne vide et ajoutez des pieds de page le cas échéant: "BREAKING CHANGE: <description>" pour les changements incompatibles et "Refs: <référence>" pour les tickets liés
+- N'inventez pas de numéros de tickets`, BodyWidth)
+	case "de":
+		return fmt.Sprintf(`
+
+Schreiben Sie außerdem einen Textkörper und gegebenenfalls Fußzeilen:
+- Lassen Sie nach der Betreffzeile eine Leerzeile und schreiben Sie einen oder mehrere Absätze, die erklären, warum die Änderung vorgenommen wurde und was sie betrifft, nicht nur was sich geändert hat
+- Umbrechen Sie den Textkörper bei %d Zeichen pro Zeile
+- Lassen Sie nach dem Textkörper eine Leerzeile und fügen Sie gegebenenfalls Fußzeilen hinzu: "BREAKING CHANGE: <Beschreibung>" für inkompatible Änderungen und "Refs: <Referenz>" für zugehörige Issues
+- Erfinden Sie keine Issue-Nummern`, BodyWidth)
+	default: // Padrão é português pt-br
+		return fmt.Sprintf(`
+
+Escreva também um corpo e, quando aplicável, rodapés:
+- Depois da linha de assunto, deixe uma linha em branco e escreva um ou mais parágrafos explicando por que a mudança foi feita e o que ela afeta, não apenas o que mudou
+- Quebre o corpo em %d caracteres por linha
+- Depois do corpo, deixe uma linha em branco e adicione rodapés quando aplicável: "BREAKING CHANGE: <descrição>" para mudanças incompatíveis e "Refs: <referência>" para issues relacionadas
+- Não invente números de issues`, BodyWidth)
+	}
+}
@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// OllamaProvider implementa a interface Provider para o Ollama (modelos locais)
+type OllamaProvider struct {
+	ProviderOptions
+}
+
+// NewOllamaProvider cria uma nova instância de OllamaProvider
+func NewOllamaProvider(opts ProviderOptions) *OllamaProvider {
+	return &OllamaProvider{ProviderOptions: opts}
+}
+
+// Estrutura para requisição ao Ollama
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  ollamaOptions `json:"options"`
+}
+
+// Opções de geração aceitas pelo Ollama
+type ollamaOptions struct {
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+// Estrutura para resposta do Ollama
+type ollamaResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+}
+
+// GenerateCommitMessage gera uma mensagem de commit com base nas mudanças usando Ollama
+func (p *OllamaProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	// Sem URL do servidor não há como chamar o Ollama; o próximo da cadeia será usado
+	if p.BaseURL == "" {
+		return "", fmt.Errorf("URL do servidor Ollama não configurada")
+	}
+
+	messages, err := buildMessages(req, p.promptBudget())
+	if err != nil {
+		return "", err
+	}
+
+	reqBody := ollamaRequest{
+		Model:    p.Model,
+		Messages: messages,
+		Stream:   false, // Não usar streaming para simplificar
+		Options: ollamaOptions{
+			NumPredict:  p.responseTokens(req),
+			Temperature: p.Temperature,
+		},
+	}
+
+	var resp ollamaResponse
+	if err := postJSON(ctx, "Ollama", p.endpoint("/api/chat"), p.Headers, reqBody, &resp); err != nil {
+		return "", err
+	}
+
+	// Verificar se a resposta contém uma mensagem
+	if resp.Message.Content == "" {
+		return "", fmt.Errorf("nenhuma resposta gerada pelo Ollama")
+	}
+
+	return resp.Message.Content, nil
+}
@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// OpenAICompatibleProvider implementa a interface Provider para qualquer API que siga
+// o formato de chat completions da OpenAI (OpenAI, Gemini, DeepSeek, OpenRouter, Grok,
+// vLLM, LM Studio, LiteLLM, etc.). Apenas a URL base, o modelo, a chave e os cabeçalhos mudam.
+type OpenAICompatibleProvider struct {
+	ProviderOptions
+	Name        string // Nome exibido nas mensagens de erro
+	KeyRequired bool   // Indica se a API exige chave
+}
+
+// NewOpenAICompatibleProvider cria uma nova instância de OpenAICompatibleProvider
+func NewOpenAICompatibleProvider(name string, keyRequired bool, opts ProviderOptions) *OpenAICompatibleProvider {
+	return &OpenAICompatibleProvider{
+		ProviderOptions: opts,
+		Name:            name,
+		KeyRequired:     keyRequired,
+	}
+}
+
+// Estrutura para a requisição a APIs compatíveis com a OpenAI
+type openAIRequest struct {
+	Model       string    `json:"model"`
+	Messages    []message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature *float64  `json:"temperature,omitempty"`
+}
+
+// Estrutura para a resposta de APIs compatíveis com a OpenAI
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// GenerateCommitMessage gera uma mensagem de commit com base nas mudanças usando a API compatível
+func (p *OpenAICompatibleProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	// Sem chave de API não há como chamar o provedor; o próximo da cadeia será usado
+	if p.KeyRequired && p.APIKey == "" {
+		return "", fmt.Errorf("%s: %w", p.Name, ErrMissingAPIKey)
+	}
+
+	messages, err := buildMessages(req, p.promptBudget())
+	if err != nil {
+		return "", err
+	}
+
+	reqBody := openAIRequest{
+		Model:       p.Model,
+		Messages:    messages,
+		MaxTokens:   p.responseTokens(req),
+		Temperature: p.Temperature,
+	}
+
+	headers := make(map[string]string, len(p.Headers)+1)
+	if p.APIKey != "" {
+		headers["Authorization"] = "Bearer " + p.APIKey
+	}
+	for key, value := range p.Headers {
+		headers[key] = value
+	}
+
+	var resp openAIResponse
+	if err := postJSON(ctx, p.Name, p.endpoint("/chat/completions"), headers, reqBody, &resp); err != nil {
+		return "", err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("nenhuma resposta gerada pela IA")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
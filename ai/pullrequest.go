@@ -0,0 +1,209 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// prMaxTokens é o limite mínimo de tokens da resposta com a descrição de um pull request
+const prMaxTokens = 1500
+
+// PullRequestTemplateFiles são os locais em que o GitHub procura o template de descrição de
+// pull requests, relativos à raiz do repositório, na ordem de busca
+var PullRequestTemplateFiles = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+}
+
+// titlePrefix reconhece marcações que o modelo às vezes coloca antes do título
+var titlePrefix = regexp.MustCompile(`(?i)^(#+\s*|\*\*)?(title|título|titulo|titre|titel)\s*:\s*(\*\*)?\s*|^#+\s*`)
+
+// PullRequest reúne o título e a descrição em Markdown gerados para um pull request
+type PullRequest struct {
+	Title string
+	Body  string
+}
+
+// String retorna o pull request como texto: o título, uma linha em branco e a descrição
+func (pr *PullRequest) String() string {
+	return pr.Title + "\n\n" + pr.Body
+}
+
+// LoadPullRequestTemplate lê o template de descrição de pull requests. Um caminho relativo é
+// resolvido a partir da raiz do repositório; sem caminho, os locais usados pelo GitHub são
+// procurados. Retorna "" quando o repositório não tem template.
+func LoadPullRequestTemplate(repoPath string, file string) (string, error) {
+	candidates := PullRequestTemplateFiles
+	if file != "" {
+		candidates = []string{file}
+	}
+	for _, candidate := range candidates {
+		if !filepath.IsAbs(candidate) {
+			candidate = filepath.Join(repoPath, candidate)
+		}
+		data, err := os.ReadFile(candidate)
+		if errors.Is(err, os.ErrNotExist) && file == "" {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler template de pull request: %w", err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// GeneratePullRequest gera o título e a descrição do pull request a partir das alterações e
+// das mensagens dos commits do branch (req.Commits), seguindo o template, se houver
+func GeneratePullRequest(ctx context.Context, provider Provider, req Request) (*PullRequest, error) {
+	req.Task = TaskPullRequest
+	answer, err := provider.GenerateCommitMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	pr := parsePullRequest(answer)
+	if pr.Title == "" {
+		return nil, fmt.Errorf("resposta sem título de pull request")
+	}
+	return pr, nil
+}
+
+// parsePullRequest separa o título (a primeira linha não vazia) da descrição, removendo
+// cercas de código e rótulos como "Title:" que envolvem a resposta
+func parsePullRequest(answer string) *PullRequest {
+	text := strings.TrimSpace(answer)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text[strings.Index(text, "\n")+1:], "\n")
+		text = strings.TrimSpace(strings.TrimSuffix(text, "```"))
+	}
+
+	title, body, _ := strings.Cut(text, "\n")
+	title = strings.TrimSpace(titlePrefix.ReplaceAllString(strings.TrimSpace(title), ""))
+	title = strings.Trim(strings.TrimSuffix(title, "**"), "`\"' ")
+	return &PullRequest{Title: title, Body: strings.TrimSpace(body)}
+}
+
+// renderCommitList lista as mensagens dos commits do branch, uma por item
+func renderCommitList(messages []string) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(strings.TrimSpace(msg), "\n", "\n  "))
+	}
+	return b.String()
+}
+
+// heuristicPullRequest monta um pull request sem chamadas de rede: o título é a mensagem
+// heurística das alterações e a descrição lista os commits e os arquivos alterados
+func heuristicPullRequest(title string, req Request) string {
+	headings := map[string][3]string{
+		"en": {"Summary", "Commits", "Changes"},
+		"es": {"Resumen", "Commits", "Cambios"},
+		"fr": {"Résumé", "Commits", "Modifications"},
+		"de": {"Zusammenfassung", "Commits", "Änderungen"},
+	}
+	h, ok := headings[req.Language]
+	if !ok {
+		h = [3]string{"Resumo", "Commits", "Alterações"}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n## %s\n\n%s\n", title, h[0], title)
+	if len(req.Commits) > 0 {
+		fmt.Fprintf(&b, "\n## %s\n\n", h[1])
+		for _, msg := range req.Commits {
+			subject, _, _ := strings.Cut(msg, "\n")
+			fmt.Fprintf(&b, "- %s\n", subject)
+		}
+	}
+	fmt.Fprintf(&b, "\n## %s\n\n", h[2])
+	for _, f := range req.ChangeSet.Files {
+		fmt.Fprintf(&b, "- `%s` (%s, +%d/-%d)\n", f.Path, f.Status, f.Added, f.Deleted)
+	}
+	return b.String()
+}
+
+// getPullRequestPrompt retorna o pedido de título e descrição do pull request no idioma solicitado
+func getPullRequestPrompt(changes string, commits []string, template string, language string) string {
+	list := renderCommitList(commits)
+	switch language {
+	case "en":
+		format := "Use these Markdown sections: ## Summary, ## Motivation, ## Changes (grouped by area or component), ## Testing (how the changes were or should be verified) and ## Risk (what could break and how to roll back)."
+		if template != "" {
+			format = "Fill in the repository's pull request template below, keeping its headings, order and checklists; replace the instructions and HTML comments with content and leave unknown items unchecked.\n\nTemplate:\n" + template
+		}
+		return fmt.Sprintf(`Write the title and description of a pull request for the branch described below.
+
+On the first line, write only the title: a short imperative summary of the whole branch, at most 72 characters, with no prefix. Leave a blank line and write the description in Markdown. %s
+
+Describe the net result of the branch, not each commit. Do not invent issue numbers, test results or facts not supported by the changes.
+
+Commits of the branch (oldest first):
+%s
+%s`, format, list, changes)
+	case "es":
+		format := "Usa estas secciones de Markdown: ## Resumen, ## Motivación, ## Cambios (agrupados por área o componente), ## Pruebas (cómo se verificaron o deben verificarse los cambios) y ## Riesgos (qué podría fallar y cómo revertir)."
+		if template != "" {
+			format = "Completa la plantilla de pull request del repositorio a continuación, manteniendo sus títulos, orden y listas de verificación; reemplaza las instrucciones y los comentarios HTML por contenido y deja sin marcar los elementos desconocidos.\n\nPlantilla:\n" + template
+		}
+		return fmt.Sprintf(`Escribe el título y la descripción de un pull request para el branch descrito a continuación.
+
+En la primera línea, escribe solo el título: un resumen corto en modo imperativo de todo el branch, de hasta 72 caracteres, sin prefijo. Deja una línea en blanco y escribe la descripción en Markdown. %s
+
+Describe el resultado neto del branch, no cada commit. No inventes números de issues, resultados de pruebas ni hechos que los cambios no respalden.
+
+Commits del branch (del más antiguo al más reciente):
+%s
+%s`, format, list, changes)
+	case "fr":
+		format := "Utilisez ces sections Markdown: ## Résumé, ## Motivation, ## Modifications (groupées par domaine ou composant), ## Tests (comment les modifications ont été ou doivent être vérifiées) et ## Risques (ce qui pourrait casser et comment revenir en arrière)."
+		if template != "" {
+			format = "Remplissez le modèle de pull request du dépôt ci-dessous, en conservant ses titres, son ordre et ses listes de contrôle; remplacez les instructions et les commentaires HTML par du contenu et laissez décochés les éléments inconnus.\n\nModèle:\n" + template
+		}
+		return fmt.Sprintf(`Rédigez le titre et la description d'une pull request pour la branche décrite ci-dessous.
+
+Sur la première ligne, écrivez uniquement le titre: un court résumé à l'impératif de toute la branche, de 72 caractères au maximum, sans préfixe. Laissez une ligne vide et rédigez la description en Markdown. %s
+
+Décrivez le résultat net de la branche, pas chaque commit. N'inventez pas de numéros de tickets, de résultats de tests ni de faits non étayés par les modifications.
+
+Commits de la branche (du plus ancien au plus récent):
+%s
+%s`, format, list, changes)
+	case "de":
+		format := "Verwenden Sie diese Markdown-Abschnitte: ## Zusammenfassung, ## Motivation, ## Änderungen (nach Bereich oder Komponente gruppiert), ## Tests (wie die Änderungen geprüft wurden oder geprüft werden sollten) und ## Risiken (was brechen könnte und wie man es zurücknimmt)."
+		if template != "" {
+			format = "Füllen Sie die folgende Pull-Request-Vorlage des Repositorys aus und behalten Sie ihre Überschriften, Reihenfolge und Checklisten bei; ersetzen Sie Anweisungen und HTML-Kommentare durch Inhalt und lassen Sie unbekannte Punkte unmarkiert.\n\nVorlage:\n" + template
+		}
+		return fmt.Sprintf(`Schreiben Sie Titel und Beschreibung eines Pull Requests für den unten beschriebenen Branch.
+
+Schreiben Sie in die erste Zeile nur den Titel: eine kurze Zusammenfassung des gesamten Branches im Imperativ, höchstens 72 Zeichen, ohne Präfix. Lassen Sie eine Leerzeile und schreiben Sie die Beschreibung in Markdown. %s
+
+Beschreiben Sie das Nettoergebnis des Branches, nicht jeden Commit. Erfinden Sie keine Ticketnummern, Testergebnisse oder Fakten, die die Änderungen nicht belegen.
+
+Commits des Branches (älteste zuerst):
+%s
+%s`, format, list, changes)
+	default: // Padrão é português pt-br
+		format := "Use estas seções em Markdown: ## Resumo, ## Motivação, ## Alterações (agrupadas por área ou componente), ## Testes (como as mudanças foram ou devem ser verificadas) e ## Riscos (o que pode quebrar e como reverter)."
+		if template != "" {
+			format = "Preencha o template de pull request do repositório abaixo, mantendo seus títulos, ordem e checklists; substitua as instruções e os comentários HTML por conteúdo e deixe desmarcados os itens desconhecidos.\n\nTemplate:\n" + template
+		}
+		return fmt.Sprintf(`Escreva o título e a descrição de um pull request para o branch descrito abaixo.
+
+Na primeira linha, escreva apenas o título: um resumo curto no imperativo de todo o branch, com no máximo 72 caracteres, sem prefixo. Deixe uma linha em branco e escreva a descrição em Markdown. %s
+
+Descreva o resultado líquido do branch, não cada commit. Não invente números de issues, resultados de testes ou fatos que as mudanças não sustentem.
+
+Commits do branch (do mais antigo ao mais recente):
+%s
+%s`, format, list, changes)
+	}
+}
@@ -0,0 +1,303 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/commit-ai/config"
+)
+
+// DefaultProvider é o provedor usado quando nenhum outro é configurado
+const DefaultProvider = "gemini"
+
+// HeuristicProviderName é o nome do provedor heurístico, usado como último fallback
+const HeuristicProviderName = "heuristic"
+
+// ProviderInfo descreve um provedor de IA registrado
+type ProviderInfo struct {
+	Name         string                                     // Identificador usado na configuração e na flag -provider
+	DisplayName  string                                     // Nome exibido para o usuário
+	NeedsKey     bool                                       // Indica se o provedor exige chave de API
+	Offline      bool                                       // Indica se o provedor funciona sem rede
+	DefaultModel string                                     // Modelo usado quando nenhum outro é configurado
+	BaseURL      string                                     // URL base padrão da API
+	Headers      map[string]string                          // Cabeçalhos HTTP padrão do provedor
+	Key          func(cfg *config.Config) *string           // Campo da configuração que guarda a chave de API
+	ServerURL    func(cfg *config.Config) *string           // Campo da configuração que guarda a URL do servidor (opcional)
+	Model        func(cfg *config.Config) *string           // Campo da configuração que guarda o modelo (opcional)
+	New          func(cfg *config.Config) (Provider, error) // Construtor do provedor a partir da configuração
+}
+
+// Options resolve os ajustes do provedor a partir da configuração.
+// Valores definidos em cfg.Providers têm precedência sobre os campos específicos
+// (como ollama_url e ollama_model), que por sua vez têm precedência sobre os padrões do provedor.
+func (info ProviderInfo) Options(cfg *config.Config) ProviderOptions {
+	settings := cfg.ProviderSettings(info.Name)
+	opts := ProviderOptions{
+		APIKey:        settings.APIKey,
+		Model:         settings.Model,
+		BaseURL:       settings.BaseURL,
+		MaxTokens:     settings.MaxTokens,
+		Temperature:   settings.Temperature,
+		ContextWindow: settings.ContextWindow,
+		Headers:       make(map[string]string, len(info.Headers)+len(settings.Headers)),
+	}
+	for key, value := range info.Headers {
+		opts.Headers[key] = value
+	}
+	for key, value := range settings.Headers {
+		opts.Headers[key] = value
+	}
+	if opts.APIKey == "" && info.Key != nil {
+		opts.APIKey = *info.Key(cfg)
+	}
+	if opts.Model == "" && info.Model != nil {
+		opts.Model = *info.Model(cfg)
+	}
+	if opts.Model == "" {
+		opts.Model = info.DefaultModel
+	}
+	if opts.BaseURL == "" && info.ServerURL != nil {
+		opts.BaseURL = *info.ServerURL(cfg)
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = info.BaseURL
+	}
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultMaxTokens
+	}
+	return opts
+}
+
+// registry armazena os provedores registrados, indexados pelo nome
+var registry = map[string]ProviderInfo{}
+
+// registryOrder mantém a ordem de registro para exibição
+var registryOrder []string
+
+// Register adiciona um provedor ao registro.
+// Registrar dois provedores com o mesmo nome é um erro de programação e causa pânico.
+func Register(info ProviderInfo) {
+	if info.Name == "" || info.New == nil {
+		panic("ai: provedor registrado sem nome ou construtor")
+	}
+	if _, exists := registry[info.Name]; exists {
+		panic(fmt.Sprintf("ai: provedor %q registrado mais de uma vez", info.Name))
+	}
+	registry[info.Name] = info
+	registryOrder = append(registryOrder, info.Name)
+}
+
+// Lookup retorna as informações de um provedor registrado
+func Lookup(name string) (ProviderInfo, bool) {
+	info, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	return info, ok
+}
+
+// Resolve retorna o provedor registrado com o nome informado ou, se nenhum nome for
+// informado, o provedor padrão. Um nome desconhecido é um erro: usar outro provedor no lugar
+// enviaria as alterações a um serviço que o usuário não escolheu.
+func Resolve(name string) (ProviderInfo, error) {
+	if strings.TrimSpace(name) == "" {
+		return registry[DefaultProvider], nil
+	}
+	if err := ValidateProvider(name); err != nil {
+		return ProviderInfo{}, err
+	}
+	info, _ := Lookup(name)
+	return info, nil
+}
+
+// Providers retorna os provedores registrados na ordem de registro
+func Providers() []ProviderInfo {
+	infos := make([]ProviderInfo, 0, len(registryOrder))
+	for _, name := range registryOrder {
+		infos = append(infos, registry[name])
+	}
+	return infos
+}
+
+// ProviderNames retorna os nomes dos provedores registrados na ordem de registro
+func ProviderNames() []string {
+	names := make([]string, len(registryOrder))
+	copy(names, registryOrder)
+	return names
+}
+
+// ValidateProvider verifica se o nome corresponde a um provedor registrado
+func ValidateProvider(name string) error {
+	if _, ok := Lookup(name); ok {
+		return nil
+	}
+	names := ProviderNames()
+	sort.Strings(names)
+	return fmt.Errorf("provedor de IA desconhecido: %q (disponíveis: %s)", name, strings.Join(names, ", "))
+}
+
+// NewProvider cria o provedor de IA configurado em cfg.AIProvider,
+// limitando cada requisição ao tempo definido na configuração
+func NewProvider(cfg *config.Config) (Provider, error) {
+	info, ok := Lookup(cfg.AIProvider)
+	if !ok {
+		return nil, ValidateProvider(cfg.AIProvider)
+	}
+	provider, err := info.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return WithTimeout(provider, cfg.Timeout()), nil
+}
+
+// NewProviderChain cria o provedor configurado em cfg.AIProvider seguido dos provedores
+// de cfg.FallbackProviders, tentados em ordem. Cada provedor repete falhas transitórias
+// conforme a política de novas tentativas, e cada tentativa respeita o tempo limite configurado.
+// Alterações maiores que a janela de contexto de um provedor são resumidas por partes antes
+// da mensagem final. Segredos são ocultados das alterações antes de chegarem a qualquer provedor
+// (veja Redact). As mensagens geradas são limpas e validadas conforme o estilo da requisição,
+// com pedidos de correção quando necessário, e mensagens com corpo são quebradas em BodyWidth colunas.
+// O provedor heurístico, que não depende de rede, é sempre o último recurso da cadeia.
+func NewProviderChain(cfg *config.Config) (Provider, error) {
+	policy := DefaultRetryPolicy()
+	if cfg.MaxRetries > 0 {
+		policy.MaxRetries = cfg.MaxRetries
+	} else if cfg.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+
+	names := append([]string{cfg.AIProvider}, cfg.FallbackProviders...)
+	names = append(names, HeuristicProviderName)
+	seen := make(map[string]bool, len(names))
+	var chain []namedProvider
+	for _, name := range names {
+		info, ok := Lookup(name)
+		if !ok {
+			return nil, ValidateProvider(name)
+		}
+		if seen[info.Name] {
+			continue
+		}
+		seen[info.Name] = true
+
+		provider, err := info.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar provedor %s: %w", info.Name, err)
+		}
+		if !info.Offline {
+			provider = WithRetry(info.DisplayName, WithTimeout(provider, cfg.Timeout()), policy)
+			provider = WithSummarization(provider, info.Options(cfg).promptBudget(), cfg.SummaryConcurrency)
+		}
+		chain = append(chain, namedProvider{name: info.DisplayName, provider: provider})
+	}
+
+	denyPaths := append(append([]string{}, DefaultRedactPaths...), cfg.RedactPaths...)
+	var provider Provider = &fallbackProvider{chain: chain}
+	if len(chain) == 1 {
+		provider = chain[0].provider
+	}
+	attempts := DefaultRepairAttempts
+	if cfg.RepairAttempts != 0 {
+		attempts = cfg.RepairAttempts
+	}
+	provider = WithValidation(provider, attempts)
+	return &formattingProvider{provider: WithRedaction(provider, denyPaths)}, nil
+}
+
+// registerBuiltin registra um provedor embutido cujo construtor recebe os ajustes já resolvidos
+func registerBuiltin(info ProviderInfo, constructor func(info ProviderInfo, opts ProviderOptions) (Provider, error)) {
+	info.New = func(cfg *config.Config) (Provider, error) {
+		return constructor(info, info.Options(cfg))
+	}
+	Register(info)
+}
+
+// newOpenAICompatible constrói um provedor a partir de uma predefinição compatível com a OpenAI
+func newOpenAICompatible(info ProviderInfo, opts ProviderOptions) (Provider, error) {
+	if opts.BaseURL == "" {
+		return nil, fmt.Errorf("provedor %s exige base_url configurada", info.Name)
+	}
+	if opts.Model == "" {
+		return nil, fmt.Errorf("provedor %s exige model configurado", info.Name)
+	}
+	return NewOpenAICompatibleProvider(info.DisplayName, info.NeedsKey, opts), nil
+}
+
+func init() {
+	registerBuiltin(ProviderInfo{
+		Name:         "openai",
+		DisplayName:  "OpenAI",
+		NeedsKey:     true,
+		DefaultModel: "gpt-3.5-turbo",
+		BaseURL:      "https://api.openai.com/v1",
+		Key:          func(cfg *config.Config) *string { return &cfg.OpenAIKey },
+	}, newOpenAICompatible)
+	registerBuiltin(ProviderInfo{
+		Name:         "gemini",
+		DisplayName:  "Gemini",
+		NeedsKey:     true,
+		DefaultModel: "gemini-2.0-flash",
+		BaseURL:      "https://generativelanguage.googleapis.com/v1beta/openai",
+		Key:          func(cfg *config.Config) *string { return &cfg.GeminiKey },
+	}, newOpenAICompatible)
+	registerBuiltin(ProviderInfo{
+		Name:         "claude",
+		DisplayName:  "Claude (Anthropic)",
+		NeedsKey:     true,
+		DefaultModel: "claude-3-haiku-20240307",
+		BaseURL:      "https://api.anthropic.com/v1",
+		Key:          func(cfg *config.Config) *string { return &cfg.ClaudeKey },
+	}, func(_ ProviderInfo, opts ProviderOptions) (Provider, error) {
+		return NewClaudeProvider(opts), nil
+	})
+	registerBuiltin(ProviderInfo{
+		Name:         "deepseek",
+		DisplayName:  "DeepSeek",
+		NeedsKey:     true,
+		DefaultModel: "deepseek-coder",
+		BaseURL:      "https://api.deepseek.com/v1",
+		Key:          func(cfg *config.Config) *string { return &cfg.DeepSeekKey },
+	}, newOpenAICompatible)
+	registerBuiltin(ProviderInfo{
+		Name:         "openrouter",
+		DisplayName:  "OpenRouter",
+		NeedsKey:     true,
+		DefaultModel: "openai/gpt-4-turbo",
+		BaseURL:      "https://openrouter.ai/api/v1",
+		Headers: map[string]string{
+			"HTTP-Referer": "https://github.com/user/commit-ai", // Requerido pelo OpenRouter
+			"X-Title":      "Commit-AI",                         // Recomendado pelo OpenRouter
+		},
+		Key: func(cfg *config.Config) *string { return &cfg.OpenRouterKey },
+	}, newOpenAICompatible)
+	registerBuiltin(ProviderInfo{
+		Name:         "grok",
+		DisplayName:  "Grok (xAI)",
+		NeedsKey:     true,
+		DefaultModel: "grok-1",
+		BaseURL:      "https://api.grok.ai/v1",
+		Key:          func(cfg *config.Config) *string { return &cfg.GrokKey },
+	}, newOpenAICompatible)
+	registerBuiltin(ProviderInfo{
+		Name:         "ollama",
+		DisplayName:  "Ollama (modelo local)",
+		NeedsKey:     false,
+		DefaultModel: "llama3",
+		BaseURL:      "http://localhost:11434",
+		ServerURL:    func(cfg *config.Config) *string { return &cfg.OllamaURL },
+		Model:        func(cfg *config.Config) *string { return &cfg.OllamaModel },
+	}, func(_ ProviderInfo, opts ProviderOptions) (Provider, error) {
+		return NewOllamaProvider(opts), nil
+	})
+	registerBuiltin(ProviderInfo{
+		Name:        "openai-compatible",
+		DisplayName: "OpenAI-compatible",
+		NeedsKey:    false,
+	}, newOpenAICompatible)
+	registerBuiltin(ProviderInfo{
+		Name:        HeuristicProviderName,
+		DisplayName: "Heurístico (offline)",
+		Offline:     true,
+	}, func(_ ProviderInfo, _ ProviderOptions) (Provider, error) {
+		return NewHeuristicProvider(), nil
+	})
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/commit-ai/git"
+)
+
+// statusLabels traduz o tipo de alteração para exibição no prompt
+var statusLabels = map[git.FileStatus]string{
+	git.StatusAdded:    "adicionado",
+	git.StatusModified: "modificado",
+	git.StatusDeleted:  "removido",
+	git.StatusRenamed:  "renomeado",
+}
+
+// RenderChanges converte um ChangeSet no texto enviado ao modelo: um resumo por arquivo
+// seguido do diff unificado de cada arquivo
+func RenderChanges(cs *git.ChangeSet) string {
+	if cs.IsEmpty() {
+		return "Nenhuma alteração detectada"
+	}
+
+	var b strings.Builder
+	b.WriteString(renderSummary(cs))
+	b.WriteString("\nDiff completo:\n")
+	for _, f := range cs.Files {
+		b.WriteString(renderFileDiff(f))
+	}
+	return b.String()
+}
+
+// renderSummary lista os arquivos alterados com status, linguagem e estatísticas
+func renderSummary(cs *git.ChangeSet) string {
+	added, deleted := cs.Stats()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Alterações em %d arquivo(s) (+%d -%d):\n", len(cs.Files), added, deleted)
+	for _, f := range cs.Files {
+		name := f.Path
+		if f.Status == git.StatusRenamed && f.OldPath != f.Path {
+			name = f.OldPath + " -> " + f.Path
+		}
+		if f.Binary {
+			fmt.Fprintf(&b, "- %s (%s, binário)\n", name, statusLabels[f.Status])
+			continue
+		}
+		if f.Withheld {
+			fmt.Fprintf(&b, "- %s (%s, conteúdo retido)\n", name, statusLabels[f.Status])
+			continue
+		}
+		fmt.Fprintf(&b, "- %s (%s, %s, +%d -%d)\n", name, statusLabels[f.Status], f.Language, f.Added, f.Deleted)
+	}
+	return b.String()
+}
+
+// renderFileDiff gera o diff unificado de um único arquivo
+func renderFileDiff(f git.FileChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s (%s)\n", f.OldPath, f.Path, statusLabels[f.Status])
+	if f.Binary {
+		b.WriteString("[arquivo binário]\n")
+		return b.String()
+	}
+	if f.Withheld {
+		b.WriteString("[conteúdo não enviado por política de privacidade]\n")
+		return b.String()
+	}
+	for _, h := range f.Hunks {
+		b.WriteString(renderHunk(h))
+	}
+	return b.String()
+}
+
+// renderHunk gera o texto de um trecho alterado
+func renderHunk(h git.Hunk) string {
+	return h.Header + "\n" + strings.Join(h.Lines, "\n") + "\n"
+}
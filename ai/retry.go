@@ -0,0 +1,178 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPError representa uma resposta de erro devolvida pela API de um provedor
+type HTTPError struct {
+	Provider   string        // Nome do provedor
+	StatusCode int           // Código de status HTTP
+	Body       string        // Corpo da resposta
+	RetryAfter time.Duration // Espera sugerida pelo cabeçalho Retry-After (zero se ausente)
+}
+
+// Error implementa a interface error
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("erro na API do %s (status %d): %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Temporary indica se o erro é transitório (limite de requisições ou falha do servidor)
+func (e *HTTPError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After, em segundos ou como data HTTP
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// RetryPolicy define como as requisições com falha transitória são repetidas
+type RetryPolicy struct {
+	MaxRetries int           // Número de novas tentativas após a primeira
+	BaseDelay  time.Duration // Espera base, dobrada a cada tentativa
+	MaxDelay   time.Duration // Espera máxima entre tentativas
+}
+
+// DefaultRetryPolicy retorna a política de novas tentativas padrão
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 2,
+		BaseDelay:  time.Second,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// backoff calcula a espera antes da tentativa informada (começando em 1), com jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	// Jitter entre metade e o valor integral da espera
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isRetryable indica se vale a pena repetir a requisição após o erro. Só são repetidas
+// falhas de rede, prazos excedidos de uma tentativa isolada e respostas HTTP transitórias;
+// respostas inválidas ou vazias, chaves ausentes e templates com erro se repetiriam a cada
+// tentativa e vão direto para o próximo provedor da cadeia.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Temporary()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryProvider repete requisições com falha transitória usando backoff exponencial
+type retryProvider struct {
+	name     string
+	provider Provider
+	policy   RetryPolicy
+}
+
+// WithRetry envolve um provedor para repetir requisições com falha transitória.
+// Esperas sugeridas pelo cabeçalho Retry-After são respeitadas; se excederem a espera
+// máxima da política, o provedor desiste para que o próximo da cadeia seja usado.
+func WithRetry(name string, provider Provider, policy RetryPolicy) Provider {
+	if policy.MaxRetries <= 0 {
+		return provider
+	}
+	return &retryProvider{name: name, provider: provider, policy: policy}
+}
+
+// GenerateCommitMessage tenta gerar a mensagem repetindo falhas transitórias
+func (p *retryProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	attempts := p.policy.MaxRetries + 1
+	for attempt := 1; ; attempt++ {
+		msg, err := p.provider.GenerateCommitMessage(ctx, req)
+		if err == nil {
+			return msg, nil
+		}
+
+		log.Printf("Tentativa %d/%d com %s falhou: %v", attempt, attempts, p.name, err)
+		if attempt >= attempts || !isRetryable(err) || ctx.Err() != nil {
+			return "", err
+		}
+
+		delay := p.policy.backoff(attempt)
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+			if httpErr.RetryAfter > p.policy.MaxDelay {
+				log.Printf("%s pediu espera de %s (Retry-After), acima do máximo de %s; desistindo", p.name, httpErr.RetryAfter, p.policy.MaxDelay)
+				return "", err
+			}
+			delay = httpErr.RetryAfter
+		}
+
+		log.Printf("Nova tentativa com %s em %s...", p.name, delay.Round(time.Millisecond))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// namedProvider associa um provedor ao nome usado nos logs
+type namedProvider struct {
+	name     string
+	provider Provider
+}
+
+// fallbackProvider tenta uma cadeia ordenada de provedores até que um deles tenha sucesso
+type fallbackProvider struct {
+	chain []namedProvider
+}
+
+// GenerateCommitMessage tenta cada provedor da cadeia em ordem
+func (p *fallbackProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	var errs []error
+	for i, candidate := range p.chain {
+		msg, err := candidate.provider.GenerateCommitMessage(ctx, req)
+		if err == nil {
+			return msg, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", candidate.name, err))
+		if i+1 < len(p.chain) {
+			log.Printf("Provedor %s falhou (%v); usando %s como fallback", candidate.name, err, p.chain[i+1].name)
+		}
+	}
+	return "", fmt.Errorf("todos os provedores de IA falharam: %w", errors.Join(errs...))
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// Modos de uso do escopo deduzido dos caminhos alterados
+const (
+	ScopeSuggest = "suggest" // O escopo é sugerido ao modelo (padrão)
+	ScopeEnforce = "enforce" // O escopo da mensagem gerada é substituído pelo deduzido
+	ScopeOff     = "off"     // O escopo fica a critério do modelo
+)
+
+// CheckScopeMode valida o modo de uso do escopo (uma string vazia equivale a "suggest")
+func CheckScopeMode(mode string) error {
+	switch mode {
+	case "", ScopeSuggest, ScopeEnforce, ScopeOff:
+		return nil
+	}
+	return fmt.Errorf("modo de escopo desconhecido: %s (use %s, %s ou %s)", mode, ScopeSuggest, ScopeEnforce, ScopeOff)
+}
+
+// ScopeFor deduz o escopo das alterações conforme scope_mode e o mapa scopes da configuração.
+// enforce indica que o escopo deve ser imposto à mensagem gerada.
+func ScopeFor(repo *git.Repository, cs *git.ChangeSet, cfg *config.Config) (scope string, enforce bool, err error) {
+	if err := CheckScopeMode(cfg.ScopeMode); err != nil {
+		return "", false, err
+	}
+	if cfg.ScopeMode == ScopeOff {
+		return "", false, nil
+	}
+
+	scope = repo.InferScope(cs, cfg.Scopes)
+	return scope, cfg.ScopeMode == ScopeEnforce && scope != "", nil
+}
+
+// replaceScope troca o escopo da linha de assunto pelo informado.
+// Mensagens fora do formato Conventional Commits são retornadas sem alteração.
+func replaceScope(msg string, scope string) string {
+	header, rest, hasRest := strings.Cut(msg, "\n")
+	m := headerPattern.FindStringSubmatch(header)
+	if scope == "" || m == nil || !isConventionalType(m[1]) || m[2] == scope {
+		return msg
+	}
+
+	header = m[1] + "(" + scope + ")" + m[3] + ": " + m[4]
+	if !hasRest {
+		return header
+	}
+	return header + "\n" + rest
+}
+
+// getScopeInstructions retorna a sugestão de escopo no idioma solicitado
+func getScopeInstructions(scope string, language string) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf("\n\nThe changes belong to the %q component; use %q as the scope.", scope, scope)
+	case "es":
+		return fmt.Sprintf("\n\nLos cambios pertenecen al componente %q; usa %q como alcance.", scope, scope)
+	case "fr":
+		return fmt.Sprintf("\n\nLes modifications concernent le composant %q; utilisez %q comme portée.", scope, scope)
+	case "de":
+		return fmt.Sprintf("\n\nDie Änderungen gehören zur Komponente %q; verwenden Sie %q als Bereich.", scope, scope)
+	default: // Padrão é português pt-br
+		return fmt.Sprintf("\n\nAs mudanças pertencem ao componente %q; use %q como escopo.", scope, scope)
+	}
+}
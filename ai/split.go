@@ -0,0 +1,228 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/user/commit-ai/git"
+)
+
+// splitLine reconhece uma linha do plano de divisão: "<número>: arquivo, arquivo"
+var splitLine = regexp.MustCompile(`^\s*(?:[-*]\s*)?(?:[Cc]ommit\s*)?(\d+)\s*[:.)-]\s*(.+)$`)
+
+// SplitChanges divide as alterações em grupos que podem virar commits atômicos.
+// A divisão é pedida ao provedor a partir de uma proposta heurística (por documentação,
+// testes, CI, build e componente); se o provedor falhar ou responder fora do formato,
+// a proposta heurística é usada. A divisão é por arquivo: cada arquivo aparece em exatamente
+// um grupo, com todos os seus trechos.
+func SplitChanges(ctx context.Context, provider Provider, req Request) ([]*git.ChangeSet, error) {
+	if req.ChangeSet.IsEmpty() {
+		return nil, nil
+	}
+	proposal := heuristicGroups(req.ChangeSet)
+	if len(req.ChangeSet.Files) == 1 {
+		return proposal, nil
+	}
+
+	req.Task = TaskSplit
+	req.Split = proposal
+	answer, err := provider.GenerateCommitMessage(ctx, req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Printf("Erro ao planejar a divisão das alterações (%v); usando a divisão heurística", err)
+		return proposal, nil
+	}
+
+	groups := parseSplitPlan(answer, req.ChangeSet)
+	if len(groups) == 0 {
+		log.Printf("Plano de divisão fora do formato esperado; usando a divisão heurística")
+		return proposal, nil
+	}
+	return groups, nil
+}
+
+// heuristicGroups agrupa os arquivos por assunto: documentação, CI, build e código por
+// componente. Testes acompanham o código do mesmo componente, quando ele também mudou.
+func heuristicGroups(cs *git.ChangeSet) []*git.ChangeSet {
+	byKey := make(map[string]*git.ChangeSet)
+	var keys []string
+	add := func(key string, f git.FileChange) {
+		if byKey[key] == nil {
+			byKey[key] = &git.ChangeSet{Staged: cs.Staged}
+			keys = append(keys, key)
+		}
+		byKey[key].Files = append(byKey[key].Files, f)
+	}
+
+	components := make(map[string]bool)
+	for _, f := range cs.Files {
+		if inferFileType(f.Path) == "" {
+			components[component(f.Path)] = true
+		}
+	}
+
+	for _, f := range cs.Files {
+		switch kind := inferFileType(f.Path); kind {
+		case "":
+			add("code:"+component(f.Path), f)
+		case "test":
+			if components[component(f.Path)] {
+				add("code:"+component(f.Path), f)
+			} else {
+				add("test", f)
+			}
+		default:
+			add(kind, f)
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return groupOrder(keys[i]) < groupOrder(keys[j])
+	})
+	groups := make([]*git.ChangeSet, len(keys))
+	for i, key := range keys {
+		groups[i] = byKey[key]
+	}
+	return groups
+}
+
+// component retorna o diretório de primeiro nível do arquivo ("." para a raiz)
+func component(file string) string {
+	dir := path.Dir(file)
+	if i := strings.Index(dir, "/"); i >= 0 {
+		dir = dir[:i]
+	}
+	return dir
+}
+
+// groupOrder ordena os grupos: código primeiro, depois testes, build, CI e documentação
+func groupOrder(key string) int {
+	switch {
+	case strings.HasPrefix(key, "code:"):
+		return 0
+	case key == "test":
+		return 1
+	case key == "build":
+		return 2
+	case key == "ci":
+		return 3
+	}
+	return 4
+}
+
+// parseSplitPlan interpreta o plano de divisão devolvido pelo provedor.
+// Arquivos desconhecidos são ignorados, arquivos repetidos ficam no primeiro grupo e os
+// arquivos que o plano esqueceu formam um grupo final.
+func parseSplitPlan(answer string, cs *git.ChangeSet) []*git.ChangeSet {
+	files := make(map[string]git.FileChange, len(cs.Files))
+	for _, f := range cs.Files {
+		files[f.Path] = f
+	}
+
+	type numbered struct {
+		n     int
+		group *git.ChangeSet
+	}
+	var plan []numbered
+	assigned := make(map[string]bool)
+	for _, line := range strings.Split(answer, "\n") {
+		m := splitLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		group := &git.ChangeSet{Staged: cs.Staged}
+		for _, name := range strings.Split(m[2], ",") {
+			name = strings.Trim(strings.TrimSpace(name), "`\"'")
+			if f, ok := files[name]; ok && !assigned[name] {
+				assigned[name] = true
+				group.Files = append(group.Files, f)
+			}
+		}
+		if len(group.Files) > 0 {
+			plan = append(plan, numbered{n, group})
+		}
+	}
+	if len(plan) == 0 {
+		return nil
+	}
+	sort.SliceStable(plan, func(i, j int) bool { return plan[i].n < plan[j].n })
+
+	groups := make([]*git.ChangeSet, len(plan))
+	for i, p := range plan {
+		groups[i] = p.group
+	}
+	rest := &git.ChangeSet{Staged: cs.Staged}
+	for _, f := range cs.Files {
+		if !assigned[f.Path] {
+			rest.Files = append(rest.Files, f)
+		}
+	}
+	if len(rest.Files) > 0 {
+		groups = append(groups, rest)
+	}
+	return groups
+}
+
+// renderSplitProposal apresenta a proposta heurística no formato esperado da resposta
+func renderSplitProposal(groups []*git.ChangeSet) string {
+	var b strings.Builder
+	for i, group := range groups {
+		fmt.Fprintf(&b, "%d: %s\n", i+1, strings.Join(group.Paths(), ", "))
+	}
+	return b.String()
+}
+
+// getSplitPrompt retorna o pedido de divisão das alterações em commits no idioma solicitado
+func getSplitPrompt(changes string, proposal string, language string) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf(`Split the following changes into the smallest set of atomic commits, one per logical concern (for example a bug fix, a refactoring and a documentation update go into separate commits). Keep files that depend on each other in the same commit.
+
+Initial proposal based on file paths:
+%s
+Answer only with one line per commit, in the order they should be made, using exactly the file paths above: "<number>: <file>, <file>". Every file must appear in exactly one commit.
+
+%s`, proposal, changes)
+	case "es":
+		return fmt.Sprintf(`Divide los siguientes cambios en el menor conjunto de commits atómicos, uno por cada asunto lógico (por ejemplo, una corrección, una refactorización y una actualización de documentación van en commits separados). Mantén juntos los archivos que dependen entre sí.
+
+Propuesta inicial basada en las rutas de los archivos:
+%s
+Responde solo con una línea por commit, en el orden en que deben hacerse, usando exactamente las rutas anteriores: "<número>: <archivo>, <archivo>". Cada archivo debe aparecer en exactamente un commit.
+
+%s`, proposal, changes)
+	case "fr":
+		return fmt.Sprintf(`Divisez les modifications suivantes en un ensemble minimal de commits atomiques, un par sujet logique (par exemple, une correction, une refactorisation et une mise à jour de la documentation vont dans des commits séparés). Gardez ensemble les fichiers qui dépendent les uns des autres.
+
+Proposition initiale basée sur les chemins des fichiers:
+%s
+Répondez uniquement avec une ligne par commit, dans l'ordre où ils doivent être faits, en utilisant exactement les chemins ci-dessus: "<numéro>: <fichier>, <fichier>". Chaque fichier doit apparaître dans exactement un commit.
+
+%s`, proposal, changes)
+	case "de":
+		return fmt.Sprintf(`Teilen Sie die folgenden Änderungen in die kleinstmögliche Menge atomarer Commits auf, einen pro logischem Thema (zum Beispiel gehören ein Bugfix, ein Refactoring und eine Dokumentationsänderung in getrennte Commits). Halten Sie voneinander abhängige Dateien im selben Commit.
+
+Erster Vorschlag auf Basis der Dateipfade:
+%s
+Antworten Sie nur mit einer Zeile pro Commit, in der Reihenfolge, in der sie erstellt werden sollen, und verwenden Sie genau die obigen Pfade: "<Nummer>: <Datei>, <Datei>". Jede Datei muss in genau einem Commit vorkommen.
+
+%s`, proposal, changes)
+	default: // Padrão é português pt-br
+		return fmt.Sprintf(`Divida as seguintes mudanças no menor conjunto de commits atômicos, um por assunto lógico (por exemplo, uma correção, uma refatoração e uma atualização de documentação vão em commits separados). Mantenha juntos os arquivos que dependem uns dos outros.
+
+Proposta inicial baseada nos caminhos dos arquivos:
+%s
+Responda apenas com uma linha por commit, na ordem em que devem ser feitos, usando exatamente os caminhos acima: "<número>: <arquivo>, <arquivo>". Cada arquivo deve aparecer em exatamente um commit.
+
+%s`, proposal, changes)
+	}
+}
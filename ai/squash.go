@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderSquashed lista as mensagens dos commits combinados, uma por item
+func renderSquashed(messages []string) string {
+	var b strings.Builder
+	for i, msg := range messages {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, strings.ReplaceAll(strings.TrimSpace(msg), "\n", "\n   "))
+	}
+	return b.String()
+}
+
+// getSquashInstructions retorna, no idioma solicitado, o pedido de uma mensagem única para
+// os commits combinados, cujas mensagens individuais são listadas da mais antiga à mais recente
+func getSquashInstructions(messages []string, language string) string {
+	list := renderSquashed(messages)
+	switch language {
+	case "en":
+		return fmt.Sprintf(`
+
+These changes are the net result of the following commits, which will be squashed into one (oldest first):
+%s
+Write a single cohesive message for the combined change. Describe the final result shown in the changes, not the history: leave out steps that were reverted or fixed later, work-in-progress notes and fixups.`, list)
+	case "es":
+		return fmt.Sprintf(`
+
+Estos cambios son el resultado neto de los siguientes commits, que se combinarán en uno solo (del más antiguo al más reciente):
+%s
+Escribe un único mensaje cohesivo para el cambio combinado. Describe el resultado final que muestran los cambios, no el historial: omite pasos revertidos o corregidos después, notas de trabajo en progreso y fixups.`, list)
+	case "fr":
+		return fmt.Sprintf(`
+
+Ces modifications sont le résultat net des commits suivants, qui seront combinés en un seul (du plus ancien au plus récent):
+%s
+Écrivez un message unique et cohérent pour la modification combinée. Décrivez le résultat final visible dans les modifications, pas l'historique: omettez les étapes annulées ou corrigées ensuite, les notes de travail en cours et les fixups.`, list)
+	case "de":
+		return fmt.Sprintf(`
+
+Diese Änderungen sind das Nettoergebnis der folgenden Commits, die zu einem einzigen zusammengefasst werden (älteste zuerst):
+%s
+Schreiben Sie eine einzige zusammenhängende Nachricht für die kombinierte Änderung. Beschreiben Sie das Endergebnis, das die Änderungen zeigen, nicht den Verlauf: Lassen Sie später zurückgenommene oder korrigierte Schritte, Zwischenstände und Fixups weg.`, list)
+	default: // Padrão é português pt-br
+		return fmt.Sprintf(`
+
+Estas mudanças são o resultado líquido dos seguintes commits, que serão combinados em um só (do mais antigo ao mais recente):
+%s
+Escreva uma única mensagem coesa para a mudança combinada. Descreva o resultado final mostrado nas mudanças, não o histórico: deixe de fora etapas desfeitas ou corrigidas depois, anotações de trabalho em andamento e fixups.`, list)
+	}
+}
@@ -0,0 +1,374 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/user/commit-ai/config"
+)
+
+// DefaultStyle é o estilo de mensagem usado quando nenhum outro é configurado
+const DefaultStyle = "conventional"
+
+// Style descreve um estilo de mensagem de commit: as regras enviadas no prompt e o
+// validador aplicado à mensagem gerada
+type Style struct {
+	Name     string                    // Identificador usado na configuração e na flag -style
+	Validate func(msg string) []string // Retorna os problemas da mensagem (vazio quando válida)
+
+	rules func(language string) string // Regras do estilo no idioma solicitado (nil usa as regras do template padrão)
+}
+
+// styles contém os estilos embutidos, indexados pelo nome
+var styles = map[string]*Style{
+	"conventional": {Name: "conventional", Validate: ValidateConventional},
+	"angular":      {Name: "angular", Validate: validateAngular, rules: angularRules},
+	"gitmoji":      {Name: "gitmoji", Validate: validateGitmoji, rules: gitmojiRules},
+	"kernel":       {Name: "kernel", Validate: validateKernel, rules: kernelRules},
+	"plain":        {Name: "plain", Validate: validatePlain, rules: plainRules},
+}
+
+// StyleNames retorna os nomes dos estilos disponíveis, incluindo "custom"
+func StyleNames() []string {
+	names := make([]string, 0, len(styles)+1)
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append(names, "custom")
+}
+
+// NewStyle retorna o estilo com o nome informado. O estilo "custom" é montado a partir das
+// regras definidas pelo usuário e exige um prompt; o padrão da linha de assunto é opcional.
+func NewStyle(name string, custom *config.CustomStyle) (*Style, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultStyle
+	}
+	if style, ok := styles[name]; ok {
+		return style, nil
+	}
+	if name != "custom" {
+		return nil, fmt.Errorf("estilo de commit desconhecido: %s (disponíveis: %s)", name, strings.Join(StyleNames(), ", "))
+	}
+
+	if custom == nil || strings.TrimSpace(custom.Prompt) == "" {
+		return nil, fmt.Errorf("estilo custom exige custom_style.prompt configurado")
+	}
+	style := &Style{
+		Name:  "custom",
+		rules: func(string) string { return strings.TrimSpace(custom.Prompt) },
+	}
+	if custom.Pattern != "" {
+		pattern, err := regexp.Compile(custom.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom_style.pattern inválido: %w", err)
+		}
+		style.Validate = func(msg string) []string {
+			subject, problems := checkSubject(msg, 0)
+			if subject != "" && !pattern.MatchString(subject) {
+				problems = append(problems, fmt.Sprintf("a linha de assunto deve seguir o padrão %s", pattern))
+			}
+			return problems
+		}
+	}
+	return style, nil
+}
+
+// StyleFor retorna o estilo configurado em cfg.CommitStyle
+func StyleFor(cfg *config.Config) (*Style, error) {
+	return NewStyle(cfg.CommitStyle, cfg.CustomStyle)
+}
+
+// style retorna o estilo da requisição (Conventional Commits por padrão)
+func (r Request) style() *Style {
+	if r.Style == nil {
+		return styles[DefaultStyle]
+	}
+	return r.Style
+}
+
+// Rules retorna as regras do estilo no idioma solicitado, como enviadas ao modelo.
+// Retorna vazio para o estilo conventional, cujas regras fazem parte do template padrão.
+func (s *Style) Rules(language string) string {
+	if s.rules == nil {
+		return ""
+	}
+	return s.rules(language)
+}
+
+// checkSubject verifica as regras comuns a todos os estilos: mensagem não vazia, linha em
+// branco após o assunto e tamanho máximo do assunto (zero usa maxSubjectLength).
+// Retorna a linha de assunto e os problemas encontrados.
+func checkSubject(msg string, max int) (string, []string) {
+	msg = strings.TrimSpace(strings.ReplaceAll(msg, "\r\n", "\n"))
+	if msg == "" {
+		return "", []string{"a mensagem está vazia"}
+	}
+	if max <= 0 {
+		max = maxSubjectLength
+	}
+
+	var problems []string
+	lines := strings.Split(msg, "\n")
+	if n := len([]rune(lines[0])); n > max {
+		problems = append(problems, fmt.Sprintf("a linha de assunto tem %d caracteres; o máximo é %d", n, max))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "deve haver uma linha em branco entre o assunto e o corpo")
+	}
+	return lines[0], problems
+}
+
+// angularTypes são os tipos aceitos pela convenção do Angular
+var angularTypes = []string{"build", "ci", "docs", "feat", "fix", "perf", "refactor", "test"}
+
+// validateAngular aplica a convenção do Angular: a gramática do Conventional Commits com
+// tipos restritos, descrição iniciada em minúscula e sem ponto final
+func validateAngular(msg string) []string {
+	problems := ValidateConventional(msg)
+	cm, err := ParseConventional(msg)
+	if err != nil {
+		return problems
+	}
+	if !containsFold(angularTypes, cm.Type) {
+		problems = append(problems, fmt.Sprintf("tipo %q não é aceito pela convenção do Angular; use um de: %s", cm.Type, strings.Join(angularTypes, ", ")))
+	}
+	if r := firstRune(cm.Description); unicode.IsUpper(r) {
+		problems = append(problems, "a descrição deve começar com letra minúscula")
+	}
+	if strings.HasSuffix(cm.Description, ".") {
+		problems = append(problems, "a descrição não deve terminar com ponto")
+	}
+	return problems
+}
+
+// gitmojis associa os tipos de alteração aos emojis do padrão gitmoji
+var gitmojis = map[string]string{
+	"feat":     "✨",
+	"fix":      "🐛",
+	"docs":     "📝",
+	"style":    "🎨",
+	"refactor": "♻️",
+	"perf":     "⚡️",
+	"test":     "✅",
+	"build":    "📦️",
+	"ci":       "👷",
+	"chore":    "🔧",
+	"revert":   "⏪️",
+}
+
+// gitmojiShortcode reconhece emojis escritos como código (ex.: ":sparkles:")
+var gitmojiShortcode = regexp.MustCompile(`^:[a-z0-9_+-]+:`)
+
+// validateGitmoji exige que a linha de assunto comece com um emoji seguido da descrição
+func validateGitmoji(msg string) []string {
+	subject, problems := checkSubject(msg, 0)
+	if subject == "" {
+		return problems
+	}
+
+	rest := subject
+	if code := gitmojiShortcode.FindString(subject); code != "" {
+		rest = subject[len(code):]
+	} else if r := firstRune(subject); unicode.Is(unicode.So, r) {
+		rest = strings.TrimLeftFunc(subject, func(r rune) bool {
+			return unicode.Is(unicode.So, r) || unicode.Is(unicode.Mn, r) || r == '\uFE0F' || r == '\u200D'
+		})
+	} else {
+		problems = append(problems, "a linha de assunto deve começar com um emoji do gitmoji (ex.: ✨ ou :sparkles:)")
+		return problems
+	}
+	if !strings.HasPrefix(rest, " ") || strings.TrimSpace(rest) == "" {
+		problems = append(problems, "o emoji deve ser seguido de um espaço e da descrição")
+	}
+	return problems
+}
+
+// kernelSubject reconhece o formato "subsistema: resumo" (com subsistemas aninhados opcionais)
+var kernelSubject = regexp.MustCompile(`^[A-Za-z0-9_./-]+(?:: [A-Za-z0-9_./-]+)*: \S`)
+
+// validateKernel aplica o formato do kernel Linux: "subsistema: resumo" com até 75 caracteres
+func validateKernel(msg string) []string {
+	subject, problems := checkSubject(msg, 75)
+	if subject == "" {
+		return problems
+	}
+	if !kernelSubject.MatchString(subject) {
+		problems = append(problems, "a linha de assunto deve seguir o formato \"subsistema: resumo\"")
+	} else if subsystem := subject[:strings.Index(subject, ":")]; isConventionalType(subsystem) {
+		problems = append(problems, fmt.Sprintf("%q é um tipo do Conventional Commits, não um subsistema", subsystem))
+	}
+	if strings.HasSuffix(subject, ".") {
+		problems = append(problems, "a linha de assunto não deve terminar com ponto")
+	}
+	return problems
+}
+
+// prefixedSubject reconhece assuntos com prefixo de tipo ou subsistema ("feat: ...", "net: ...")
+var prefixedSubject = regexp.MustCompile(`^[\w./-]+(?:\([^)]*\))?!?: `)
+
+// validatePlain exige uma frase simples no imperativo: sem prefixo, iniciada em maiúscula,
+// sem ponto final e com até 72 caracteres
+func validatePlain(msg string) []string {
+	subject, problems := checkSubject(msg, 72)
+	if subject == "" {
+		return problems
+	}
+	if prefixedSubject.MatchString(subject) {
+		problems = append(problems, "a linha de assunto não deve ter prefixo de tipo ou escopo")
+	}
+	if r := firstRune(subject); unicode.IsLetter(r) && !unicode.IsUpper(r) {
+		problems = append(problems, "a linha de assunto deve começar com letra maiúscula")
+	}
+	if strings.HasSuffix(subject, ".") {
+		problems = append(problems, "a linha de assunto não deve terminar com ponto")
+	}
+	return problems
+}
+
+// firstRune retorna o primeiro caractere do texto
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
+
+// containsFold indica se a lista contém o valor, sem diferenciar maiúsculas
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// angularRules retorna as regras da convenção do Angular no idioma solicitado
+func angularRules(language string) string {
+	switch language {
+	case "en":
+		return `1. Use the format "type(scope): subject"; the scope is optional
+2. Use one of these types: build, ci, docs, feat, fix, perf, refactor, test
+3. Write the subject in the imperative mood, starting with a lowercase letter and without a period at the end
+4. Keep the first line under 100 characters`
+	case "es":
+		return `1. Usa el formato "tipo(alcance): asunto"; el alcance es opcional
+2. Usa uno de estos tipos: build, ci, docs, feat, fix, perf, refactor, test
+3. Escribe el asunto en modo imperativo, comenzando con minúscula y sin punto final
+4. Mantén la primera línea por debajo de 100 caracteres`
+	case "fr":
+		return `1. Utilisez le format "type(portée): sujet"; la portée est facultative
+2. Utilisez l'un de ces types: build, ci, docs, feat, fix, perf, refactor, test
+3. Écrivez le sujet à l'impératif, en commençant par une minuscule et sans point final
+4. Gardez la première ligne sous 100 caractères`
+	case "de":
+		return `1. Verwenden Sie das Format "Typ(Bereich): Betreff"; der Bereich ist optional
+2. Verwenden Sie einen dieser Typen: build, ci, docs, feat, fix, perf, refactor, test
+3. Schreiben Sie den Betreff im Imperativ, beginnend mit einem Kleinbuchstaben und ohne Punkt am Ende
+4. Halten Sie die erste Zeile unter 100 Zeichen`
+	default: // Padrão é português pt-br
+		return `1. Use o formato "tipo(escopo): assunto"; o escopo é opcional
+2. Use um destes tipos: build, ci, docs, feat, fix, perf, refactor, test
+3. Escreva o assunto no imperativo, começando com letra minúscula e sem ponto final
+4. Mantenha a primeira linha com menos de 100 caracteres`
+	}
+}
+
+// gitmojiRules retorna as regras do padrão gitmoji no idioma solicitado
+func gitmojiRules(language string) string {
+	emojis := "✨ feat, 🐛 fix, 📝 docs, 🎨 style, ♻️ refactor, ⚡️ perf, ✅ test, 📦️ build, 👷 ci, 🔧 chore, ⏪️ revert"
+	switch language {
+	case "en":
+		return `1. Start the first line with the gitmoji that best describes the change, followed by a space and a short description
+2. Common gitmojis: ` + emojis + `
+3. Do not add a type prefix such as "feat:"
+4. Keep the first line under 100 characters`
+	case "es":
+		return `1. Comienza la primera línea con el gitmoji que mejor describa el cambio, seguido de un espacio y una descripción corta
+2. Gitmojis comunes: ` + emojis + `
+3. No agregues un prefijo de tipo como "feat:"
+4. Mantén la primera línea por debajo de 100 caracteres`
+	case "fr":
+		return `1. Commencez la première ligne par le gitmoji qui décrit le mieux la modification, suivi d'un espace et d'une courte description
+2. Gitmojis courants: ` + emojis + `
+3. N'ajoutez pas de préfixe de type comme "feat:"
+4. Gardez la première ligne sous 100 caractères`
+	case "de":
+		return `1. Beginnen Sie die erste Zeile mit dem Gitmoji, das die Änderung am besten beschreibt, gefolgt von einem Leerzeichen und einer kurzen Beschreibung
+2. Gängige Gitmojis: ` + emojis + `
+3. Fügen Sie kein Typ-Präfix wie "feat:" hinzu
+4. Halten Sie die erste Zeile unter 100 Zeichen`
+	default: // Padrão é português pt-br
+		return `1. Comece a primeira linha com o gitmoji que melhor descreve a mudança, seguido de um espaço e uma descrição curta
+2. Gitmojis comuns: ` + emojis + `
+3. Não adicione prefixo de tipo como "feat:"
+4. Mantenha a primeira linha com menos de 100 caracteres`
+	}
+}
+
+// kernelRules retorna as regras do formato do kernel Linux no idioma solicitado
+func kernelRules(language string) string {
+	switch language {
+	case "en":
+		return `1. Use the format "subsystem: summary", where the subsystem is the affected component or directory (e.g. "net: ", "git: ", "ai/openai: ")
+2. Write the summary in the imperative mood, without a period at the end
+3. Do not use Conventional Commits types such as "feat" or "fix" as the subsystem
+4. Keep the first line under 75 characters`
+	case "es":
+		return `1. Usa el formato "subsistema: resumen", donde el subsistema es el componente o directorio afectado (ej.: "net: ", "git: ", "ai/openai: ")
+2. Escribe el resumen en modo imperativo, sin punto final
+3. No uses tipos de Conventional Commits como "feat" o "fix" como subsistema
+4. Mantén la primera línea por debajo de 75 caracteres`
+	case "fr":
+		return `1. Utilisez le format "sous-système: résumé", où le sous-système est le composant ou le répertoire concerné (ex.: "net: ", "git: ", "ai/openai: ")
+2. Écrivez le résumé à l'impératif, sans point final
+3. N'utilisez pas les types Conventional Commits comme "feat" ou "fix" comme sous-système
+4. Gardez la première ligne sous 75 caractères`
+	case "de":
+		return `1. Verwenden Sie das Format "Subsystem: Zusammenfassung", wobei das Subsystem die betroffene Komponente oder das Verzeichnis ist (z.B. "net: ", "git: ", "ai/openai: ")
+2. Schreiben Sie die Zusammenfassung im Imperativ, ohne Punkt am Ende
+3. Verwenden Sie keine Conventional-Commits-Typen wie "feat" oder "fix" als Subsystem
+4. Halten Sie die erste Zeile unter 75 Zeichen`
+	default: // Padrão é português pt-br
+		return `1. Use o formato "subsistema: resumo", onde o subsistema é o componente ou diretório afetado (ex.: "net: ", "git: ", "ai/openai: ")
+2. Escreva o resumo no imperativo, sem ponto final
+3. Não use tipos do Conventional Commits como "feat" ou "fix" como subsistema
+4. Mantenha a primeira linha com menos de 75 caracteres`
+	}
+}
+
+// plainRules retorna as regras de uma mensagem simples no imperativo, no idioma solicitado
+func plainRules(language string) string {
+	switch language {
+	case "en":
+		return `1. Write a single sentence in the imperative mood (e.g. "Add retry to provider requests")
+2. Start with a capital letter and do not end with a period
+3. Do not add type or scope prefixes such as "feat:" or "fix(api):"
+4. Keep the first line under 72 characters`
+	case "es":
+		return `1. Escribe una sola frase en modo imperativo (ej.: "Agrega reintentos a las solicitudes")
+2. Comienza con mayúscula y no termines con punto
+3. No agregues prefijos de tipo o alcance como "feat:" o "fix(api):"
+4. Mantén la primera línea por debajo de 72 caracteres`
+	case "fr":
+		return `1. Écrivez une seule phrase à l'impératif (ex.: "Ajoute des nouvelles tentatives aux requêtes")
+2. Commencez par une majuscule et ne terminez pas par un point
+3. N'ajoutez pas de préfixes de type ou de portée comme "feat:" ou "fix(api):"
+4. Gardez la première ligne sous 72 caractères`
+	case "de":
+		return `1. Schreiben Sie einen einzigen Satz im Imperativ (z.B. "Wiederholungen für Anfragen hinzufügen")
+2. Beginnen Sie mit einem Großbuchstaben und enden Sie nicht mit einem Punkt
+3. Fügen Sie keine Typ- oder Bereichspräfixe wie "feat:" oder "fix(api):" hinzu
+4. Halten Sie die erste Zeile unter 72 Zeichen`
+	default: // Padrão é português pt-br
+		return `1. Escreva uma única frase no imperativo (ex.: "Adiciona novas tentativas às requisições")
+2. Comece com letra maiúscula e não termine com ponto
+3. Não adicione prefixos de tipo ou escopo como "feat:" ou "fix(api):"
+4. Mantenha a primeira linha com menos de 72 caracteres`
+	}
+}
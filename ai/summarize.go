@@ -0,0 +1,276 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/user/commit-ai/git"
+)
+
+const (
+	// DefaultSummaryConcurrency é o número padrão de resumos parciais gerados em paralelo
+	DefaultSummaryConcurrency = 4
+
+	// summaryMaxTokens é o limite de tokens da resposta de cada resumo parcial
+	summaryMaxTokens = 400
+)
+
+// summarizingProvider gera a mensagem em duas etapas quando as alterações não cabem no
+// orçamento do modelo, nem mesmo sem o conteúdo de baixo valor: primeiro resume grupos de
+// arquivos (em paralelo) e depois pede a mensagem final a partir dos resumos. Se os resumos
+// também não couberem, eles são resumidos novamente até caberem (redução hierárquica).
+type summarizingProvider struct {
+	provider    Provider
+	budget      int
+	concurrency int
+
+	// Os resumos do último ChangeSet são reaproveitados nos pedidos de correção da mensagem
+	mu        sync.Mutex
+	changes   *git.ChangeSet
+	summaries []string
+}
+
+// WithSummarization envolve um provedor para ativar automaticamente o resumo hierárquico
+// quando o ChangeSet exceder o orçamento de tokens informado mesmo após o corte de lockfiles,
+// código gerado e alterações só de espaços
+func WithSummarization(provider Provider, budget int, concurrency int) Provider {
+	if concurrency <= 0 {
+		concurrency = DefaultSummaryConcurrency
+	}
+	return &summarizingProvider{provider: provider, budget: budget, concurrency: concurrency}
+}
+
+// GenerateCommitMessage gera a mensagem diretamente ou por resumo hierárquico
+func (p *summarizingProvider) GenerateCommitMessage(ctx context.Context, req Request) (string, error) {
+	if (req.Task != TaskCommitMessage && req.Task != TaskPullRequest) || len(req.Summaries) > 0 || req.ChangeSet.IsEmpty() {
+		return p.provider.GenerateCommitMessage(ctx, req)
+	}
+	// Se o orçamento só descarta conteúdo de baixo valor, o diff cabe em uma única chamada
+	if _, truncated := renderWithBudget(req.ChangeSet, p.budget); !truncated {
+		return p.provider.GenerateCommitMessage(ctx, req)
+	}
+
+	summaries, err := p.summarize(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	final := req
+	final.Summaries = summaries
+	return p.provider.GenerateCommitMessage(ctx, final)
+}
+
+// summarize resume o ChangeSet da requisição em partes que caibam no orçamento
+func (p *summarizingProvider) summarize(ctx context.Context, req Request) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.changes == req.ChangeSet {
+		return p.summaries, nil
+	}
+
+	chunks := chunkChangeSet(req.ChangeSet, p.budget)
+	log.Printf("Alterações excedem o limite do modelo; resumindo %d grupo(s) de arquivos", len(chunks))
+
+	inputs := make([]Request, len(chunks))
+	for i, chunk := range chunks {
+		inputs[i] = Request{ChangeSet: chunk, Language: req.Language, Task: TaskSummarize}
+	}
+	summaries, err := p.summarizeAll(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reduzir os resumos até que caibam no orçamento
+	for level := 1; EstimateTokens(strings.Join(summaries, "\n\n")) > p.budget && len(summaries) > 1; level++ {
+		groups := chunkSummaries(summaries, p.budget)
+		if len(groups) >= len(summaries) {
+			break
+		}
+		log.Printf("Resumos ainda excedem o limite; reduzindo %d resumo(s) em %d (nível %d)", len(summaries), len(groups), level)
+
+		inputs = make([]Request, len(groups))
+		for i, group := range groups {
+			inputs[i] = Request{Language: req.Language, Task: TaskSummarize, Summaries: group}
+		}
+		if summaries, err = p.summarizeAll(ctx, inputs); err != nil {
+			return nil, err
+		}
+	}
+
+	p.changes, p.summaries = req.ChangeSet, summaries
+	return summaries, nil
+}
+
+// summarizeAll gera os resumos das requisições em paralelo, limitado pela concorrência configurada
+func (p *summarizingProvider) summarizeAll(ctx context.Context, inputs []Request) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]string, len(inputs))
+	errs := make([]error, len(inputs))
+	semaphore := make(chan struct{}, p.concurrency)
+
+	var wg sync.WaitGroup
+	for i := range inputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			summary, err := p.provider.GenerateCommitMessage(ctx, inputs[i])
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+			results[i] = strings.TrimSpace(summary)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("erro ao resumir grupo %d de %d: %w", i+1, len(inputs), err)
+		}
+	}
+	return results, nil
+}
+
+// chunkChangeSet divide o ChangeSet em grupos que cabem no orçamento, mantendo juntos os
+// arquivos de um mesmo diretório sempre que possível
+func chunkChangeSet(cs *git.ChangeSet, budget int) []*git.ChangeSet {
+	files := make([]git.FileChange, len(cs.Files))
+	copy(files, cs.Files)
+	sort.SliceStable(files, func(i, j int) bool {
+		return path.Dir(files[i].Path) < path.Dir(files[j].Path)
+	})
+
+	// Cada grupo mantém os demais campos do ChangeSet (Staged, Commit, Base)
+	newChunk := func() *git.ChangeSet {
+		chunk := *cs
+		chunk.Files = nil
+		return &chunk
+	}
+
+	var chunks []*git.ChangeSet
+	current := newChunk()
+	used := 0
+	for _, f := range files {
+		cost := EstimateTokens(renderFileDiff(f))
+		if len(current.Files) > 0 && used+cost > budget {
+			chunks = append(chunks, current)
+			current = newChunk()
+			used = 0
+		}
+		current.Files = append(current.Files, f)
+		used += cost
+	}
+	if len(current.Files) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// chunkSummaries agrupa resumos consecutivos em blocos que cabem no orçamento
+func chunkSummaries(summaries []string, budget int) [][]string {
+	var groups [][]string
+	var current []string
+	used := 0
+	for _, summary := range summaries {
+		cost := EstimateTokens(summary) + 1
+		if len(current) > 0 && used+cost > budget {
+			groups = append(groups, current)
+			current = nil
+			used = 0
+		}
+		current = append(current, summary)
+		used += cost
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// renderSummaries apresenta os resumos parciais no lugar do diff
+func renderSummaries(cs *git.ChangeSet, summaries []string) string {
+	var b strings.Builder
+	if !cs.IsEmpty() {
+		added, deleted := cs.Stats()
+		fmt.Fprintf(&b, "Alterações em %d arquivo(s) (+%d -%d), por diretório:\n", len(cs.Files), added, deleted)
+		for _, line := range directoryStats(cs) {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Resumos parciais das alterações:\n")
+	for i, summary := range summaries {
+		fmt.Fprintf(&b, "\n[Parte %d]\n%s\n", i+1, summary)
+	}
+	return b.String()
+}
+
+// directoryStats resume a quantidade de arquivos e linhas alteradas por diretório
+func directoryStats(cs *git.ChangeSet) []string {
+	type stats struct{ files, added, deleted int }
+	byDir := make(map[string]*stats)
+	for _, f := range cs.Files {
+		dir := path.Dir(f.Path)
+		if byDir[dir] == nil {
+			byDir[dir] = &stats{}
+		}
+		byDir[dir].files++
+		byDir[dir].added += f.Added
+		byDir[dir].deleted += f.Deleted
+	}
+
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	lines := make([]string, len(dirs))
+	for i, dir := range dirs {
+		s := byDir[dir]
+		lines[i] = fmt.Sprintf("- %s/: %d arquivo(s) (+%d -%d)\n", dir, s.files, s.added, s.deleted)
+	}
+	return lines
+}
+
+// getSummaryPrompt retorna o prompt de resumo parcial no idioma solicitado
+func getSummaryPrompt(changes string, language string) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf(`Summarize the following code changes in 2 to 5 short bullet points. Describe what changed and why, naming the affected components. Do not write a commit message.
+
+%s`, changes)
+	case "es":
+		return fmt.Sprintf(`Resume los siguientes cambios de código en 2 a 5 viñetas cortas. Describe qué cambió y por qué, nombrando los componentes afectados. No escribas un mensaje de commit.
+
+%s`, changes)
+	case "fr":
+		return fmt.Sprintf(`Résumez les modifications de code suivantes en 2 à 5 puces courtes. Décrivez ce qui a changé et pourquoi, en nommant les composants concernés. N'écrivez pas de message de commit.
+
+%s`, changes)
+	case "de":
+		return fmt.Sprintf(`Fassen Sie die folgenden Codeänderungen in 2 bis 5 kurzen Stichpunkten zusammen. Beschreiben Sie, was sich geändert hat und warum, und nennen Sie die betroffenen Komponenten. Schreiben Sie keine Commit-Nachricht.
+
+%s`, changes)
+	default: // Padrão é português pt-br
+		return fmt.Sprintf(`Resuma as seguintes mudanças no código em 2 a 5 tópicos curtos. Descreva o que mudou e por quê, citando os componentes afetados. Não escreva uma mensagem de commit.
+
+%s`, changes)
+	}
+}
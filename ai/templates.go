@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// RepoTemplatesDir é o diretório de templates de prompt dentro do repositório
+const RepoTemplatesDir = ".commit-ai/templates"
+
+// ErrTemplate indica um template de prompt inválido; não adianta repetir a requisição
+var ErrTemplate = errors.New("erro no template de prompt")
+
+// embeddedTemplates contém os templates padrão, usados quando o usuário não define os seus
+//
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// PromptData reúne as variáveis disponíveis nos templates de prompt
+type PromptData struct {
+	Diff          string           // Alterações formatadas para o modelo (ou os resumos parciais)
+	Files         []git.FileChange // Arquivos alterados
+	Branch        string           // Branch atual
+	RecentCommits []string         // Mensagens dos commits recentes, da mais nova para a mais antiga
+	Language      string           // Idioma da mensagem de commit
+	Style         string           // Nome do estilo da mensagem
+	StyleRules    string           // Regras do estilo no idioma solicitado (vazio para conventional)
+	Scopes        []string         // Escopos permitidos, inferidos do histórico (vazio sem restrição)
+	Scope         string           // Escopo deduzido dos caminhos alterados
+}
+
+// templateFuncs são as funções auxiliares disponíveis nos templates
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// PromptTemplates resolve os templates de prompt por nome e idioma.
+// Cada template é procurado como "<nome>.<idioma>.tmpl" e depois "<nome>.tmpl" em cada
+// origem, na ordem: repositório, diretório global do usuário e templates embutidos.
+type PromptTemplates struct {
+	sources []map[string]*template.Template
+}
+
+// defaultTemplates contém apenas os templates embutidos
+var defaultTemplates = &PromptTemplates{sources: []map[string]*template.Template{mustParseEmbedded()}}
+
+// LoadTemplates carrega os templates de prompt do repositório (.commit-ai/templates) e do
+// diretório global (~/.commit-ai/templates), com os templates embutidos como padrão.
+// Erros de sintaxe nos templates são informados já no carregamento.
+func LoadTemplates(repoPath string) (*PromptTemplates, error) {
+	var dirs []string
+	if repoPath != "" {
+		dirs = append(dirs, filepath.Join(repoPath, RepoTemplatesDir))
+	}
+	if dir, err := config.TemplatesDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	templates := &PromptTemplates{}
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		parsed, err := parseTemplates(os.DirFS(dir))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar templates de %s: %w", dir, err)
+		}
+		templates.sources = append(templates.sources, parsed)
+	}
+	templates.sources = append(templates.sources, defaultTemplates.sources...)
+	return templates, nil
+}
+
+// parseTemplates interpreta todos os arquivos .tmpl do diretório, indexados pelo nome sem extensão
+func parseTemplates(fsys fs.FS) (map[string]*template.Template, error) {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := template.New(file).Funcs(templateFuncs).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, err
+		}
+		parsed[strings.TrimSuffix(file, ".tmpl")] = tmpl
+	}
+	return parsed, nil
+}
+
+// mustParseEmbedded interpreta os templates embutidos; um erro indica um template padrão inválido
+func mustParseEmbedded() map[string]*template.Template {
+	sub, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		panic(err)
+	}
+	parsed, err := parseTemplates(sub)
+	if err != nil {
+		panic(fmt.Sprintf("template embutido inválido: %v", err))
+	}
+	return parsed
+}
+
+// render executa o template com o nome informado no idioma solicitado
+func (t *PromptTemplates) render(name string, language string, data PromptData) (string, error) {
+	if t == nil {
+		t = defaultTemplates
+	}
+
+	for _, source := range t.sources {
+		for _, key := range []string{name + "." + language, name} {
+			tmpl, ok := source[key]
+			if !ok {
+				continue
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				return "", fmt.Errorf("%w %s: %v", ErrTemplate, tmpl.Name(), err)
+			}
+			return strings.TrimSpace(b.String()), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s não encontrado", ErrTemplate, name)
+}
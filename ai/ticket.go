@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/user/commit-ai/config"
+)
+
+// DefaultTicketPattern reconhece identificadores de tickets como PROJ-1234
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// Posições da referência ao ticket na mensagem de commit
+const (
+	TicketFooter = "footer" // Rodapé "Refs: PROJ-1234"
+	TicketPrefix = "prefix" // Antes da descrição: "feat: PROJ-1234 adiciona login"
+	TicketScope  = "scope"  // Como escopo: "feat(PROJ-1234): adiciona login"
+)
+
+// TicketMatcher extrai referências de tickets do nome do branch
+type TicketMatcher struct {
+	Placement string // Onde a referência é colocada na mensagem
+	pattern   *regexp.Regexp
+}
+
+// TicketMatcherFor cria o extrator de tickets da configuração.
+// Quando ticket_placement não está definido, o extrator não encontra nenhum ticket.
+func TicketMatcherFor(cfg *config.Config) (*TicketMatcher, error) {
+	switch cfg.TicketPlacement {
+	case "":
+		return &TicketMatcher{}, nil
+	case TicketFooter, TicketPrefix, TicketScope:
+	default:
+		return nil, fmt.Errorf("posição de ticket desconhecida: %s (use %s, %s ou %s)", cfg.TicketPlacement, TicketFooter, TicketPrefix, TicketScope)
+	}
+
+	expr := cfg.TicketPattern
+	if expr == "" {
+		expr = DefaultTicketPattern
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("padrão de ticket inválido: %w", err)
+	}
+	return &TicketMatcher{Placement: cfg.TicketPlacement, pattern: pattern}, nil
+}
+
+// Extract retorna os tickets encontrados no nome do branch, sem repetições.
+// Se o padrão tiver um grupo de captura, apenas o primeiro grupo é usado.
+func (m *TicketMatcher) Extract(branch string) []string {
+	if m == nil || m.pattern == nil || branch == "" {
+		return nil
+	}
+
+	var tickets []string
+	seen := make(map[string]bool)
+	for _, match := range m.pattern.FindAllStringSubmatch(branch, -1) {
+		ticket := match[0]
+		if len(match) > 1 {
+			ticket = match[1]
+		}
+		if ticket != "" && !seen[ticket] {
+			seen[ticket] = true
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// applyTickets insere as referências aos tickets na mensagem, na posição informada.
+// Tickets já citados na mensagem não são repetidos. Nas posições prefix e scope, mensagens
+// fora do formato Conventional Commits recebem os tickets no início da linha de assunto.
+func applyTickets(msg string, tickets []string, placement string) string {
+	var missing []string
+	for _, ticket := range tickets {
+		if !strings.Contains(msg, ticket) {
+			missing = append(missing, ticket)
+		}
+	}
+	if len(missing) == 0 {
+		return msg
+	}
+
+	refs := strings.Join(missing, ", ")
+	if placement == TicketFooter {
+		return appendFooter(msg, "Refs: "+refs)
+	}
+
+	header, rest, hasRest := strings.Cut(msg, "\n")
+	m := headerPattern.FindStringSubmatch(header)
+	switch {
+	case m == nil || !isConventionalType(m[1]):
+		header = strings.Join(missing, " ") + " " + header
+	case placement == TicketScope:
+		header = m[1] + "(" + refs + ")" + m[3] + ": " + m[4]
+	default:
+		header = m[1]
+		if m[2] != "" {
+			header += "(" + m[2] + ")"
+		}
+		header += m[3] + ": " + strings.Join(missing, " ") + " " + m[4]
+	}
+	if !hasRest {
+		return header
+	}
+	return header + "\n" + rest
+}
@@ -3,46 +3,185 @@ package config
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
+)
+
+const (
+	// SigningPassphraseEnv é a variável de ambiente com a senha da chave de assinatura
+	SigningPassphraseEnv = "COMMIT_AI_SIGNING_PASSPHRASE"
+
+	// DefaultRequestTimeout é o tempo limite padrão, em segundos, de cada requisição à IA
+	DefaultRequestTimeout = 60
+
+	// DefaultHistorySamples é o número padrão de mensagens do histórico usadas como exemplo
+	DefaultHistorySamples = 10
 )
 
 // Config armazena a configuração da aplicação
 type Config struct {
-	AIProvider    string `json:"ai_provider"`
-	OpenAIKey     string `json:"openai_key"`
-	GeminiKey     string `json:"gemini_key"`
-	ClaudeKey     string `json:"claude_key"`     // Nova chave para API do Claude
-	DeepSeekKey   string `json:"deepseek_key"`   // Nova chave para API do DeepSeek
-	OpenRouterKey string `json:"openrouter_key"` // Nova chave para API do OpenRouter
-	GrokKey       string `json:"grok_key"`       // Nova chave para API do Grok
-	OllamaURL     string `json:"ollama_url"`     // URL do servidor Ollama
-	OllamaModel   string `json:"ollama_model"`   // Modelo Ollama a ser usado
-	RepoPath      string `json:"repo_path"`
-	AutoCommit    bool   `json:"auto_commit"`
-	CommitStyle   string `json:"commit_style"`
-	Language      string `json:"language"` // Idioma para as mensagens de commit
+	AIProvider     string `json:"ai_provider"`
+	OpenAIKey      string `json:"openai_key"`
+	GeminiKey      string `json:"gemini_key"`
+	ClaudeKey      string `json:"claude_key"`     // Nova chave para API do Claude
+	DeepSeekKey    string `json:"deepseek_key"`   // Nova chave para API do DeepSeek
+	OpenRouterKey  string `json:"openrouter_key"` // Nova chave para API do OpenRouter
+	GrokKey        string `json:"grok_key"`       // Nova chave para API do Grok
+	OllamaURL      string `json:"ollama_url"`     // URL do servidor Ollama
+	OllamaModel    string `json:"ollama_model"`   // Modelo Ollama a ser usado
+	RepoPath       string `json:"repo_path"`
+	AutoCommit     bool   `json:"auto_commit"`
+	CommitStyle    string `json:"commit_style"`
+	Language       string `json:"language"`        // Idioma para as mensagens de commit
+	RequestTimeout int    `json:"request_timeout"` // Tempo limite de cada requisição à IA, em segundos
+	Body           bool   `json:"body,omitempty"`  // Gerar corpo e rodapés além da linha de assunto
+
+	// Provedores tentados em ordem quando o principal falha (ex.: ["openai", "ollama"])
+	FallbackProviders []string `json:"fallback_providers,omitempty"`
+	// Novas tentativas por provedor após falhas transitórias (0 usa o padrão, negativo desativa)
+	MaxRetries int `json:"max_retries,omitempty"`
+	// Pedidos de correção quando a mensagem gerada não segue o estilo configurado (0 usa o padrão, negativo desativa)
+	RepairAttempts int `json:"repair_attempts,omitempty"`
+	// Resumos parciais gerados em paralelo quando as alterações excedem o limite do modelo (0 usa o padrão)
+	SummaryConcurrency int `json:"summary_concurrency,omitempty"`
+	// Caminhos adicionais cujo conteúdo nunca é enviado aos provedores, apenas o nome (ex.: ["secrets/", "*.tfvars"])
+	RedactPaths []string `json:"redact_paths,omitempty"`
+	// Mensagens recentes do branch usadas como exemplos no prompt (0 usa o padrão, negativo desativa)
+	HistorySamples int `json:"history_samples,omitempty"`
+	// Restringe os escopos da mensagem aos que se repetem no histórico do repositório
+	InferScopes bool `json:"infer_scopes,omitempty"`
+	// Escopo deduzido dos caminhos alterados: "suggest" (padrão), "enforce" ou "off"
+	ScopeMode string `json:"scope_mode,omitempty"`
+	// Mapa de caminho para escopo, com precedência sobre a inferência (ex.: {"services/billing": "billing"})
+	Scopes map[string]string `json:"scopes,omitempty"`
+	// Onde citar o ticket extraído do nome do branch: "footer", "prefix" ou "scope" (vazio desativa)
+	TicketPlacement string `json:"ticket_placement,omitempty"`
+	// Expressão regular que extrai o ticket do nome do branch (vazio usa o padrão PROJ-1234)
+	TicketPattern string `json:"ticket_pattern,omitempty"`
+	// Caminho do arquivo com a chave privada OpenPGP (armored) usada para assinar os commits sem
+	// o gpg, quando commit.gpgsign está ativo ("~/" é o diretório do usuário)
+	SigningKey string `json:"signing_key,omitempty"`
+	// Autor dos commits no formato "Nome <email>" (vazio usa a identidade configurada no git)
+	Author string `json:"author,omitempty"`
+	// Coautores adicionados a todo commit, como "Nome <email>", apelido, e-mail ou parte do nome
+	CoAuthors []string `json:"co_authors,omitempty"`
+	// Arquivo com a equipe ("apelido: Nome <email>" por linha), relativo à raiz do repositório
+	// (vazio usa .commit-ai/coauthors)
+	CoAuthorRoster string `json:"co_author_roster,omitempty"`
+	// Perguntar quais coautores incluir, sugerindo a equipe e os autores recentes
+	SuggestCoAuthors bool `json:"suggest_co_authors,omitempty"`
+	// Adicionar o rodapé Signed-off-by (Developer Certificate of Origin)
+	SignOff bool `json:"sign_off,omitempty"`
+
+	// Regras do estilo de commit "custom" (usadas quando commit_style é "custom")
+	CustomStyle *CustomStyle `json:"custom_style,omitempty"`
+
+	// Ajustes por provedor (modelo, endpoint, limite de tokens e temperatura), indexados pelo nome
+	Providers map[string]ProviderConfig `json:"providers,omitempty"`
+}
+
+// CustomStyle descreve um estilo de mensagem de commit definido pelo usuário
+type CustomStyle struct {
+	Prompt  string `json:"prompt"`            // Regras do estilo, incluídas no prompt enviado ao provedor
+	Pattern string `json:"pattern,omitempty"` // Expressão regular que a linha de assunto deve seguir (opcional)
+}
+
+// ProviderConfig armazena os ajustes específicos de um provedor de IA.
+// Campos vazios usam os valores padrão do provedor.
+type ProviderConfig struct {
+	Model       string            `json:"model,omitempty"`       // Modelo a ser usado
+	BaseURL     string            `json:"base_url,omitempty"`    // URL base da API (ex.: gateway ou proxy interno)
+	MaxTokens   int               `json:"max_tokens,omitempty"`  // Limite de tokens da resposta
+	Temperature *float64          `json:"temperature,omitempty"` // Temperatura de amostragem
+	APIKey      string            `json:"api_key,omitempty"`     // Chave de API (tem precedência sobre os campos *_key)
+	Headers     map[string]string `json:"headers,omitempty"`     // Cabeçalhos HTTP adicionais
+	// Janela de contexto do modelo, em tokens; define quanto do diff cabe no prompt
+	// (zero deduz pelo nome do modelo)
+	ContextWindow int `json:"context_window,omitempty"`
 }
 
 // DefaultConfig retorna uma configuração padrão
 func DefaultConfig() *Config {
 	return &Config{
-		AIProvider:    "gemini",
-		OpenAIKey:     "",
-		GeminiKey:     "",
-		ClaudeKey:     "",                       // Inicializado vazio
-		DeepSeekKey:   "",                       // Inicializado vazio
-		OpenRouterKey: "",                       // Inicializado vazio
-		GrokKey:       "",                       // Inicializado vazio
-		OllamaURL:     "http://localhost:11434", // URL padrão do Ollama
-		OllamaModel:   "llama3",                 // Modelo padrão do Ollama
-		RepoPath:      "",
-		AutoCommit:    false,
-		CommitStyle:   "conventional",
-		Language:      "pt-br", // Português Brasil como idioma padrão
+		AIProvider:     "gemini",
+		OpenAIKey:      "",
+		GeminiKey:      "",
+		ClaudeKey:      "",                       // Inicializado vazio
+		DeepSeekKey:    "",                       // Inicializado vazio
+		OpenRouterKey:  "",                       // Inicializado vazio
+		GrokKey:        "",                       // Inicializado vazio
+		OllamaURL:      "http://localhost:11434", // URL padrão do Ollama
+		OllamaModel:    "llama3",                 // Modelo padrão do Ollama
+		RepoPath:       "",
+		AutoCommit:     false,
+		CommitStyle:    "conventional",
+		Language:       "pt-br", // Português Brasil como idioma padrão
+		RequestTimeout: DefaultRequestTimeout,
 	}
 }
 
+// ProviderSettings retorna os ajustes configurados para o provedor informado
+func (c *Config) ProviderSettings(name string) ProviderConfig {
+	return c.Providers[name]
+}
+
+// SetProviderSettings define os ajustes do provedor informado
+func (c *Config) SetProviderSettings(name string, settings ProviderConfig) {
+	if c.Providers == nil {
+		c.Providers = make(map[string]ProviderConfig)
+	}
+	c.Providers[name] = settings
+}
+
+// Clone retorna uma cópia independente da configuração, para ajustes que valem apenas
+// para uma execução e não devem chegar ao arquivo salvo
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.FallbackProviders = slices.Clone(c.FallbackProviders)
+	clone.RedactPaths = slices.Clone(c.RedactPaths)
+	clone.CoAuthors = slices.Clone(c.CoAuthors)
+	clone.Scopes = maps.Clone(c.Scopes)
+	if c.CustomStyle != nil {
+		style := *c.CustomStyle
+		clone.CustomStyle = &style
+	}
+	if c.Providers != nil {
+		clone.Providers = make(map[string]ProviderConfig, len(c.Providers))
+		for name, settings := range c.Providers {
+			settings.Headers = maps.Clone(settings.Headers)
+			clone.Providers[name] = settings
+		}
+	}
+	return &clone
+}
+
+// Timeout retorna o tempo limite de cada requisição à IA
+func (c *Config) Timeout() time.Duration {
+	if c.RequestTimeout <= 0 {
+		return DefaultRequestTimeout * time.Second
+	}
+	return time.Duration(c.RequestTimeout) * time.Second
+}
+
+// HistorySampleCount retorna quantas mensagens do histórico devem ser lidas (0 desativa)
+func (c *Config) HistorySampleCount() int {
+	switch {
+	case c.HistorySamples < 0:
+		return 0
+	case c.HistorySamples == 0:
+		return DefaultHistorySamples
+	}
+	return c.HistorySamples
+}
+
+// SigningPassphrase retorna a senha da chave de assinatura, lida do ambiente
+func (c *Config) SigningPassphrase() string {
+	return os.Getenv(SigningPassphraseEnv)
+}
+
 // LoadConfig carrega a configuração do arquivo
 func LoadConfig() (*Config, error) {
 	configPath, err := getConfigPath()
@@ -113,6 +252,15 @@ func SaveConfig(config *Config) error {
 	return encoder.Encode(config)
 }
 
+// TemplatesDir retorna o diretório global de templates de prompt (~/.commit-ai/templates)
+func TemplatesDir() (string, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "templates"), nil
+}
+
 // getConfigPath retorna o caminho para o arquivo de configuração
 func getConfigPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RepoConfigFile é o nome do arquivo de configuração por repositório, na raiz do repositório
+const RepoConfigFile = ".commit-ai.json"
+
+// RepoConfig reúne os ajustes que podem ser definidos por repositório.
+// Apenas preferências de formato da mensagem são aceitas: provedores, chaves e endpoints
+// continuam vindo da configuração do usuário, para que um repositório não possa desviar o diff.
+type RepoConfig struct {
+	CommitStyle string       `json:"commit_style,omitempty"` // Estilo das mensagens de commit
+	CustomStyle *CustomStyle `json:"custom_style,omitempty"` // Regras do estilo "custom"
+	Language    string       `json:"language,omitempty"`     // Idioma das mensagens de commit
+	Body        *bool        `json:"body,omitempty"`         // Gerar corpo e rodapés
+
+	HistorySamples *int  `json:"history_samples,omitempty"` // Mensagens do histórico usadas como exemplo
+	InferScopes    *bool `json:"infer_scopes,omitempty"`    // Restringir os escopos aos do histórico
+
+	ScopeMode string            `json:"scope_mode,omitempty"` // Sugerir, impor ou ignorar o escopo deduzido
+	Scopes    map[string]string `json:"scopes,omitempty"`     // Mapa de caminho para escopo
+
+	TicketPlacement string `json:"ticket_placement,omitempty"` // Onde citar o ticket do branch
+	TicketPattern   string `json:"ticket_pattern,omitempty"`   // Expressão regular do ticket
+
+	SignOff        *bool  `json:"sign_off,omitempty"`         // Adicionar Signed-off-by (DCO)
+	CoAuthorRoster string `json:"co_author_roster,omitempty"` // Arquivo com a equipe, para sugerir coautores
+}
+
+// LoadRepoConfig carrega o arquivo de configuração do repositório, se existir.
+// Retorna nil sem erro quando o repositório não tem configuração própria.
+func LoadRepoConfig(repoPath string) (*RepoConfig, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, RepoConfigFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rc RepoConfig
+	if err := json.Unmarshal(data, &rc); err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %w", RepoConfigFile, err)
+	}
+	return &rc, nil
+}
+
+// ApplyRepoConfig sobrepõe à configuração os ajustes definidos pelo repositório
+func (c *Config) ApplyRepoConfig(rc *RepoConfig) {
+	if rc == nil {
+		return
+	}
+	if rc.CommitStyle != "" {
+		c.CommitStyle = rc.CommitStyle
+	}
+	if rc.CustomStyle != nil {
+		c.CustomStyle = rc.CustomStyle
+	}
+	if rc.Language != "" {
+		c.Language = rc.Language
+	}
+	if rc.Body != nil {
+		c.Body = *rc.Body
+	}
+	if rc.HistorySamples != nil {
+		c.HistorySamples = *rc.HistorySamples
+	}
+	if rc.InferScopes != nil {
+		c.InferScopes = *rc.InferScopes
+	}
+	if rc.ScopeMode != "" {
+		c.ScopeMode = rc.ScopeMode
+	}
+	if rc.Scopes != nil {
+		c.Scopes = rc.Scopes
+	}
+	if rc.TicketPlacement != "" {
+		c.TicketPlacement = rc.TicketPlacement
+	}
+	if rc.TicketPattern != "" {
+		c.TicketPattern = rc.TicketPattern
+	}
+	if rc.SignOff != nil {
+		c.SignOff = *rc.SignOff
+	}
+	if rc.CoAuthorRoster != "" {
+		c.CoAuthorRoster = rc.CoAuthorRoster
+	}
+}
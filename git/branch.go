@@ -0,0 +1,55 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultBase retorna a referência com a qual o branch atual é comparado quando nenhuma é
+// informada: o branch padrão do remoto origin ou, na falta dele, main ou master
+func (r *Repository) DefaultBase() (string, error) {
+	if output, err := r.git("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimSpace(output), nil
+	}
+	for _, ref := range []string{"main", "master", "origin/main", "origin/master"} {
+		if _, err := r.resolve(ref); err == nil {
+			return ref, nil
+		}
+	}
+	return "", fmt.Errorf("não foi possível determinar a referência base; informe-a explicitamente")
+}
+
+// BranchCommits retorna o ponto em que o branch atual se separou da base e os commits do
+// branch a partir dele, do mais antigo para o mais recente. Commits de merge são ignorados.
+func (r *Repository) BranchCommits(base string) (mergeBase string, commits []CommitInfo, err error) {
+	output, err := r.git("merge-base", base, "HEAD")
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao comparar com %s: %w", base, err)
+	}
+	mergeBase = strings.TrimSpace(output)
+
+	// Formato: "<hash> <pais>\x00<mensagem>\x00" por commit
+	output, err = r.git("log", "--reverse", "--no-merges", "-z", "--format=%H %P%x00%B", mergeBase+"..HEAD")
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		ids := strings.Fields(fields[i])
+		c := CommitInfo{Hash: ids[0], Message: strings.TrimRight(fields[i+1], "\n")}
+		if len(ids) > 1 {
+			c.Parent = ids[1]
+		}
+		commits = append(commits, c)
+	}
+	return mergeBase, commits, nil
+}
+
+// DiffChangeSet retorna as alterações entre duas revisões
+func (r *Repository) DiffChangeSet(base string, head string) (*ChangeSet, error) {
+	output, err := r.diff("diff", "--find-renames", fmt.Sprintf("-U%d", diffContext), base, head)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diff entre %s e %s: %w", base, head, err)
+	}
+	return &ChangeSet{Base: base, Commit: head, Files: ParseDiff(output)}, nil
+}
@@ -0,0 +1,399 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// FileStatus representa o tipo de alteração sofrida por um arquivo
+type FileStatus string
+
+// Tipos de alteração reconhecidos
+const (
+	StatusAdded    FileStatus = "added"
+	StatusModified FileStatus = "modified"
+	StatusDeleted  FileStatus = "deleted"
+	StatusRenamed  FileStatus = "renamed"
+)
+
+// maxUntrackedFileSize é o tamanho máximo lido de arquivos ainda não rastreados
+const maxUntrackedFileSize = 256 * 1024
+
+// diffContext é a quantidade de linhas de contexto ao redor de cada trecho alterado
+const diffContext = 10
+
+// diffOptions deixam a saída do diff no formato que ParseDiff espera, independente da
+// configuração do usuário (cores, diff.noprefix, diff.mnemonicPrefix e drivers externos)
+var diffOptions = []string{"--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/"}
+
+// Hunk representa um trecho contíguo de alterações em um arquivo
+type Hunk struct {
+	ID       int      // Posição do trecho no arquivo, começando em 0
+	Header   string   // Linha "@@ -a,b +c,d @@ contexto"
+	OldStart int      // Linha inicial na versão antiga
+	OldLines int      // Quantidade de linhas na versão antiga
+	NewStart int      // Linha inicial na versão nova
+	NewLines int      // Quantidade de linhas na versão nova
+	Lines    []string // Linhas do trecho, com os prefixos ' ', '+', '-' ou '\'
+}
+
+// Added retorna a quantidade de linhas adicionadas no trecho
+func (h Hunk) Added() int {
+	return h.count('+')
+}
+
+// Deleted retorna a quantidade de linhas removidas no trecho
+func (h Hunk) Deleted() int {
+	return h.count('-')
+}
+
+func (h Hunk) count(prefix byte) int {
+	n := 0
+	for _, line := range h.Lines {
+		if len(line) > 0 && line[0] == prefix {
+			n++
+		}
+	}
+	return n
+}
+
+// FileChange descreve as alterações de um único arquivo
+type FileChange struct {
+	Path     string     // Caminho atual, relativo à raiz do repositório
+	OldPath  string     // Caminho anterior (diferente de Path apenas em renomeações)
+	Status   FileStatus // Tipo de alteração
+	Language string     // Linguagem ou formato deduzido da extensão
+	Binary   bool       // Indica se o arquivo é binário (sem trechos de texto)
+	Withheld bool       // Indica se o conteúdo foi retido localmente e apenas o nome é enviado
+	Hunks    []Hunk     // Trechos alterados
+	Added    int        // Total de linhas adicionadas
+	Deleted  int        // Total de linhas removidas
+}
+
+// ChangeSet reúne as alterações pendentes de um repositório
+type ChangeSet struct {
+	Staged bool         // Indica se as alterações vêm da área de stage
+	Commit string       // Commit de onde vêm as alterações (vazio para stage ou diretório de trabalho)
+	Base   string       // Revisão comparada com Commit (vazio usa o pai de Commit)
+	Files  []FileChange // Arquivos alterados
+}
+
+// Paths retorna os caminhos dos arquivos alterados
+func (cs *ChangeSet) Paths() []string {
+	paths := make([]string, len(cs.Files))
+	for i, f := range cs.Files {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// Stats retorna o total de linhas adicionadas e removidas
+func (cs *ChangeSet) Stats() (added int, deleted int) {
+	for _, f := range cs.Files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	return added, deleted
+}
+
+// IsEmpty indica se não há alterações
+func (cs *ChangeSet) IsEmpty() bool {
+	return cs == nil || len(cs.Files) == 0
+}
+
+// languages associa extensões de arquivo a linguagens ou formatos
+var languages = map[string]string{
+	".go":    "Go",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".py":    "Python",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".c":     "C",
+	".h":     "C",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".php":   "PHP",
+	".swift": "Swift",
+	".sh":    "Shell",
+	".sql":   "SQL",
+	".html":  "HTML",
+	".css":   "CSS",
+	".scss":  "SCSS",
+	".json":  "JSON",
+	".yml":   "YAML",
+	".yaml":  "YAML",
+	".toml":  "TOML",
+	".xml":   "XML",
+	".md":    "Markdown",
+	".proto": "Protocol Buffers",
+}
+
+// DetectLanguage deduz a linguagem ou o formato de um arquivo pelo nome
+func DetectLanguage(path string) string {
+	base := filepath.Base(path)
+	switch base {
+	case "Dockerfile":
+		return "Dockerfile"
+	case "Makefile":
+		return "Makefile"
+	case "go.mod", "go.sum":
+		return "Go Modules"
+	}
+	if lang, ok := languages[strings.ToLower(filepath.Ext(base))]; ok {
+		return lang
+	}
+	return "texto"
+}
+
+// ParseDiff interpreta a saída de "git diff" (formato unificado) em uma lista de arquivos
+func ParseDiff(diff string) []FileChange {
+	var files []FileChange
+	var current *FileChange
+	var hunk *Hunk
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if hunk != nil {
+			current.Hunks = append(current.Hunks, *hunk)
+			hunk = nil
+		}
+		for _, h := range current.Hunks {
+			current.Added += h.Added()
+			current.Deleted += h.Deleted()
+		}
+		current.Language = DetectLanguage(current.Path)
+		files = append(files, *current)
+		current = nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &FileChange{Status: StatusModified}
+			if parts := strings.SplitN(strings.TrimPrefix(line, "diff --git "), " b/", 2); len(parts) == 2 {
+				current.OldPath = strings.TrimPrefix(parts[0], "a/")
+				current.Path = parts[1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if hunk != nil {
+			if len(line) > 0 && strings.ContainsRune(" +-\\", rune(line[0])) {
+				hunk.Lines = append(hunk.Lines, line)
+				continue
+			}
+			current.Hunks = append(current.Hunks, *hunk)
+			hunk = nil
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			hunk = parseHunkHeader(line)
+			hunk.ID = len(current.Hunks)
+		case strings.HasPrefix(line, "new file mode"):
+			current.Status = StatusAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			current.Status = StatusDeleted
+		case strings.HasPrefix(line, "rename from "):
+			current.OldPath = strings.TrimPrefix(line, "rename from ")
+			current.Status = StatusRenamed
+		case strings.HasPrefix(line, "rename to "):
+			current.Path = strings.TrimPrefix(line, "rename to ")
+			current.Status = StatusRenamed
+		case strings.HasPrefix(line, "Binary files "), strings.HasPrefix(line, "GIT binary patch"):
+			current.Binary = true
+		}
+	}
+	flush()
+
+	return files
+}
+
+// parseHunkHeader interpreta uma linha "@@ -a,b +c,d @@"
+func parseHunkHeader(line string) *Hunk {
+	hunk := &Hunk{Header: line, OldLines: 1, NewLines: 1}
+	fields := strings.Fields(line)
+	for _, field := range fields[1:] {
+		if field == "@@" {
+			break
+		}
+		start, count := parseRange(field[1:])
+		switch field[0] {
+		case '-':
+			hunk.OldStart, hunk.OldLines = start, count
+		case '+':
+			hunk.NewStart, hunk.NewLines = start, count
+		}
+	}
+	return hunk
+}
+
+// parseRange interpreta um intervalo "inicio,quantidade" (a quantidade padrão é 1)
+func parseRange(value string) (int, int) {
+	start, count := value, "1"
+	if i := strings.IndexByte(value, ','); i >= 0 {
+		start, count = value[:i], value[i+1:]
+	}
+	s, _ := strconv.Atoi(start)
+	c, _ := strconv.Atoi(count)
+	return s, c
+}
+
+// GetChangeSet retorna as alterações pendentes do repositório de forma estruturada.
+// Se houver alterações staged, apenas elas são consideradas; caso contrário, são usadas
+// as alterações do diretório de trabalho, incluindo arquivos ainda não rastreados.
+func (r *Repository) GetChangeSet() (*ChangeSet, error) {
+	staged, err := hasStaged(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"--find-renames", fmt.Sprintf("-U%d", diffContext)}
+	if staged {
+		args = append(args, "--cached")
+	}
+	output, err := r.diff("diff", args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diff: %w", err)
+	}
+
+	cs := &ChangeSet{
+		Staged: staged,
+		Files:  ParseDiff(output),
+	}
+
+	if !staged {
+		untracked, err := r.untrackedFiles()
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range untracked {
+			cs.Files = append(cs.Files, r.untrackedChange(file))
+		}
+	}
+
+	return cs, nil
+}
+
+// diff executa um comando de diff do git ("diff" ou "diff-tree") com as opções de diffOptions
+func (r *Repository) diff(command string, args ...string) (string, error) {
+	return r.git(append(append([]string{command}, diffOptions...), args...)...)
+}
+
+// untrackedFiles retorna os arquivos ainda não rastreados pelo Git
+func (r *Repository) untrackedFiles() ([]string, error) {
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for file, fileStatus := range status {
+		if fileStatus.Worktree == git.Untracked {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// untrackedChange monta a alteração de um arquivo novo a partir do seu conteúdo
+func (r *Repository) untrackedChange(file string) FileChange {
+	change := FileChange{
+		Path:     file,
+		OldPath:  file,
+		Status:   StatusAdded,
+		Language: DetectLanguage(file),
+	}
+
+	content, err := os.ReadFile(filepath.Join(r.Path, file))
+	if err != nil || len(content) > maxUntrackedFileSize {
+		return change
+	}
+	if bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content) {
+		change.Binary = true
+		return change
+	}
+
+	text := strings.TrimSuffix(string(content), "\n")
+	if text == "" {
+		return change
+	}
+	lines := strings.Split(text, "\n")
+	hunk := Hunk{
+		Header:   fmt.Sprintf("@@ -0,0 +1,%d @@", len(lines)),
+		NewStart: 1,
+		NewLines: len(lines),
+	}
+	for _, line := range lines {
+		hunk.Lines = append(hunk.Lines, "+"+line)
+	}
+	change.Hunks = []Hunk{hunk}
+	change.Added = len(lines)
+	return change
+}
+
+// FileVersions retorna o conteúdo de um arquivo do ChangeSet antes e depois da alteração.
+// Para alterações de commits, as versões vêm de Commit e de Base (ou do pai); nos demais casos, a
+// versão anterior vem do HEAD e a nova da área de stage (se o ChangeSet for staged) ou do
+// diretório de trabalho. O lado inexistente (arquivo adicionado ou removido) é retornado como nil.
+func (r *Repository) FileVersions(cs *ChangeSet, f FileChange) (before []byte, after []byte, err error) {
+	base := "HEAD"
+	switch {
+	case cs.Base != "":
+		base = cs.Base
+	case cs.Commit != "":
+		base = cs.Commit + "^"
+	}
+	if f.Status != StatusAdded {
+		oldPath := f.OldPath
+		if oldPath == "" {
+			oldPath = f.Path
+		}
+		before, err = exec.Command("git", "-C", r.Path, "show", base+":"+oldPath).Output()
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao ler versão anterior de %s: %w", oldPath, err)
+		}
+	}
+
+	if f.Status != StatusDeleted {
+		switch {
+		case cs.Commit != "":
+			after, err = exec.Command("git", "-C", r.Path, "show", cs.Commit+":"+f.Path).Output()
+		case cs.Staged:
+			after, err = exec.Command("git", "-C", r.Path, "show", ":"+f.Path).Output()
+		default:
+			after, err = os.ReadFile(filepath.Join(r.Path, f.Path))
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao ler versão atual de %s: %w", f.Path, err)
+		}
+	}
+	return before, after, nil
+}
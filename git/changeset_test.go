@@ -0,0 +1,41 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetChangeSetIgnoresUserDiffConfig(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	writeFile(t, dir, "ação.txt", "one\n")
+	writeFile(t, dir, "plain.txt", "one\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	writeFile(t, dir, "ação.txt", "two\n")
+	writeFile(t, dir, "plain.txt", "two\n")
+
+	// Configuração do usuário que altera o formato da saída do git diff
+	global := filepath.Join(t.TempDir(), "gitconfig")
+	writeFile(t, filepath.Dir(global), filepath.Base(global),
+		"[diff]\n\tnoprefix = true\n\tmnemonicPrefix = true\n\texternal = false\n\tcolor = always\n[color]\n\tui = always\n[core]\n\tquotepath = true\n")
+	t.Setenv("GIT_CONFIG_GLOBAL", global)
+
+	repo, err := OpenRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, staged := range []bool{false, true} {
+		if staged {
+			runGit(t, dir, "add", ".")
+		}
+		cs, err := repo.GetChangeSet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"ação.txt", "plain.txt"}; !reflect.DeepEqual(cs.Paths(), want) {
+			t.Errorf("staged=%v: arquivos = %q, esperado %q", staged, cs.Paths(), want)
+		}
+	}
+}
@@ -2,17 +2,21 @@
 package git
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/go-git/go-git/v5"
 )
 
 // Repository representa um repositório Git
 type Repository struct {
-	Path string
-	repo *git.Repository
+	Path    string
+	repo    *git.Repository
+	signKey *openpgp.Entity // Chave OpenPGP usada para assinar os commits, se carregada
 }
 
 // OpenRepository abre um repositório Git existente
@@ -50,143 +54,222 @@ func (r *Repository) GetChangedFiles() ([]string, error) {
 	return changedFiles, nil
 }
 
-// GetChanges retorna as diferenças dos arquivos modificados
-func (r *Repository) GetChanges() (string, error) {
-	// Obtém a lista de arquivos modificados
-	files, err := r.GetChangedFiles()
-	if err != nil {
-		return "", err
+// hasStaged verifica se há alterações staged no repositório
+func hasStaged(repoPath string) (bool, error) {
+	err := exec.Command("git", "-C", repoPath, "diff", "--cached", "--quiet").Run()
+	// O código de saída 1 indica que há alterações staged; os demais são falhas
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return true, nil
 	}
+	return false, fmt.Errorf("erro ao verificar alterações staged: %w", err)
+}
 
-	if len(files) == 0 {
-		return "Nenhuma alteração detectada", nil
-	}
+// CommitOptions ajusta a identidade e os rodapés de um commit
+type CommitOptions struct {
+	Author     *Person  // Autor do commit (nil usa a identidade configurada no git)
+	CoAuthors  []Person // Coautores, citados em rodapés Co-authored-by
+	SignOff    bool     // Adiciona o rodapé Signed-off-by de quem faz o commit (DCO)
+	AllowEmpty bool     // Permite um commit sem alterações
+}
 
-	// Construir resumo das alterações
-	summary := fmt.Sprintf("Alterações em %d arquivo(s):\n", len(files))
-	for _, file := range files {
-		summary += fmt.Sprintf("- %s\n", file)
+// Commit realiza um commit com a mensagem especificada.
+// A mensagem é gravada como recebida, incluindo corpo e rodapés. Os hooks pre-commit,
+// prepare-commit-msg, commit-msg e post-commit são executados e o commit é assinado
+// quando a configuração do git pede (commit.gpgsign).
+func (r *Repository) Commit(message string, opts CommitOptions) error {
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return err
 	}
-	summary += "\nDetalhes das alterações:\n"
 
-	// Obter status detalhado das alterações
-	cmdStatus := exec.Command("git", "-C", r.Path, "status", "-v")
-	statusOutput, err := cmdStatus.Output()
-	if err == nil {
-		summary += "\nStatus do repositório:\n" + string(statusOutput) + "\n"
+	// Verificar se há alterações staged
+	hasStagedChanges, err := hasStaged(r.Path)
+	if err != nil {
+		return err
 	}
+	if !hasStagedChanges {
+		// Se não houver alterações staged, adicionar todos os arquivos modificados
+		changedFiles, err := r.GetChangedFiles()
+		if err != nil {
+			return err
+		}
 
-	// Obter informações sobre os arquivos alterados (extensão, tipo, etc.)
-	fileInfos := make(map[string]string)
-	for _, file := range files {
-		// Verificar tipo de arquivo
-		fileType := "arquivo"
-		if strings.HasSuffix(file, ".go") {
-			fileType = "código Go"
-		} else if strings.HasSuffix(file, ".md") {
-			fileType = "documentação (Markdown)"
-		} else if strings.HasSuffix(file, ".js") {
-			fileType = "código JavaScript"
-		} else if strings.HasSuffix(file, ".html") {
-			fileType = "arquivo HTML"
-		} else if strings.HasSuffix(file, ".css") {
-			fileType = "estilo CSS"
-		} else if strings.HasSuffix(file, ".json") {
-			fileType = "dados JSON"
-		} else if strings.HasSuffix(file, ".yml") || strings.HasSuffix(file, ".yaml") {
-			fileType = "configuração YAML"
+		// Adicionar cada arquivo modificado ao stage
+		for _, file := range changedFiles {
+			_, err = w.Add(file)
+			if err != nil {
+				return fmt.Errorf("erro ao adicionar arquivo %s: %w", file, err)
+			}
 		}
-		fileInfos[file] = fileType
 	}
 
-	fileContext := "\nContexto dos arquivos:\n"
-	for file, info := range fileInfos {
-		fileContext += fmt.Sprintf("- %s: %s\n", file, info)
+	// Executar os hooks configurados antes do commit, como o git
+	if err := r.runHook("pre-commit"); err != nil {
+		return err
 	}
-	summary += fileContext
 
-	// Executar git diff para obter as diferenças reais
-	hasStagedChanges, _ := hasStaged(r.Path)
+	// Realizar o commit preservando corpo e rodapés; como o git, terminar com uma quebra de linha
+	message = strings.TrimRight(message, "\n") + "\n"
+	if message, err = r.addTrailers(message, opts); err != nil {
+		return err
+	}
+	if message, err = r.runCommitMsgHooks(message); err != nil {
+		return err
+	}
 
-	// Obter diff mais detalhado com contexto adicional
-	var diffCmd *exec.Cmd
-	if hasStagedChanges {
-		// Obter diff das alterações staged com mais contexto
-		diffCmd = exec.Command("git", "-C", r.Path, "diff", "--cached", "--no-color", "-U10")
+	// Assinar o commit quando commit.gpgsign estiver ativo: com a chave carregada, pelo go-git;
+	// nos demais casos (gpg, ssh ou x509), pelo próprio git
+	format, err := r.signingFormat()
+	if err != nil {
+		return err
+	}
+	if format != "" && (format != signOpenPGP || r.signKey == nil) {
+		err = r.commitWithCLI(message, opts)
 	} else {
-		// Obter diff das alterações não staged com mais contexto
-		diffCmd = exec.Command("git", "-C", r.Path, "diff", "--no-color", "-U10")
+		err = r.commitWithGoGit(w, message, opts, format == signOpenPGP)
 	}
-
-	output, err := diffCmd.Output()
 	if err != nil {
-		// Se falhar, tentar com menos opções
-		diffCmd = exec.Command("git", "diff", "--no-color")
-		diffCmd.Dir = r.Path
-		output, err = diffCmd.Output()
+		return err
+	}
+
+	// O resultado do post-commit não afeta o commit já criado
+	_ = r.runHook("post-commit")
+	return nil
+}
+
+// commitWithGoGit cria o commit pelo go-git; se sign for verdadeiro, assina com a chave
+// carregada, se houver
+func (r *Repository) commitWithGoGit(w *git.Worktree, message string, opts CommitOptions, sign bool) error {
+	options := &git.CommitOptions{AllowEmptyCommits: opts.AllowEmpty}
+	if sign {
+		options.SignKey = r.signKey
+	}
+	if opts.Author != nil {
+		// Sem committer explícito, o go-git usaria o autor também como committer
+		committer, err := r.committer()
 		if err != nil {
-			// Se ainda falhar, retornar apenas o resumo
-			return summary + "\nNão foi possível obter o diff detalhado.", nil
+			return err
 		}
+		options.Author = opts.Author.signature()
+		options.Committer = committer.signature()
 	}
+	_, err := w.Commit(message, options)
+	return err
+}
 
-	// Obter estatísticas das alterações
-	statsCmd := exec.Command("git", "-C", r.Path, "diff", "--stat")
-	statsOutput, err := statsCmd.Output()
-	if err == nil {
-		summary += "\nEstatísticas das alterações:\n" + string(statsOutput) + "\n"
+// addTrailers adiciona à mensagem os rodapés Co-authored-by e Signed-off-by pedidos, com
+// "git interpret-trailers", sem repetir rodapés já presentes
+func (r *Repository) addTrailers(message string, opts CommitOptions) (string, error) {
+	var args []string
+	for _, p := range opts.CoAuthors {
+		args = append(args, "--trailer", "Co-authored-by: "+p.String())
 	}
-
-	// Aumentar o limite do diff para capturar mais contexto
-	diff := string(output)
-	maxDiffSize := 8000 // Aumentado para obter mais contexto
-	if len(diff) > maxDiffSize {
-		diff = diff[:maxDiffSize] + "...\n[Diff truncado devido ao tamanho]"
+	if opts.SignOff {
+		committer, err := r.committer()
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "--trailer", "Signed-off-by: "+committer.String())
+	}
+	if len(args) == 0 {
+		return message, nil
 	}
 
-	return summary + "\nDiff completo:\n" + diff, nil
+	cmd := r.command(append([]string{"interpret-trailers", "--if-exists", "addIfDifferent"}, args...)...)
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("erro ao adicionar rodapés: %w", err)
+	}
+	return string(output), nil
 }
 
-// hasStaged verifica se há alterações staged no repositório
-func hasStaged(repoPath string) (bool, error) {
-	cmd := exec.Command("git", "-C", repoPath, "diff", "--cached", "--quiet")
-	err := cmd.Run()
-	// Se o comando retornar código diferente de zero, há alterações staged
-	return err != nil, nil
-}
+// CommitPaths realiza um commit apenas com as alterações dos caminhos informados.
+// Se houver alterações staged, é usado o conteúdo da área de stage desses caminhos; caso
+// contrário, o do diretório de trabalho. As demais alterações staged ficam fora do commit
+// e continuam staged depois dele.
+func (r *Repository) CommitPaths(paths []string, message string, opts CommitOptions) (err error) {
+	if len(paths) == 0 {
+		return fmt.Errorf("nenhum arquivo informado para o commit")
+	}
+	selected := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		selected[p] = true
+	}
 
-// Commit realiza um commit com a mensagem especificada
-func (r *Repository) Commit(message string) error {
-	w, err := r.repo.Worktree()
+	staged, err := r.git("diff", "--cached", "--name-only", "-z")
 	if err != nil {
-		return err
+		return fmt.Errorf("erro ao listar alterações staged: %w", err)
 	}
+	staged = strings.TrimRight(staged, "\x00")
 
-	// Verificar se há alterações staged
-	hasStagedChanges, _ := hasStaged(r.Path)
-	if !hasStagedChanges {
-		// Se não houver alterações staged, adicionar todos os arquivos modificados
-		changedFiles, err := r.GetChangedFiles()
+	// Guardar e tirar do stage as alterações que não pertencem a este commit
+	var others []string
+	var saved strings.Builder
+	for _, file := range strings.Split(staged, "\x00") {
+		if file == "" || selected[file] {
+			continue
+		}
+		others = append(others, file)
+		entry, err := r.git("ls-files", "--stage", "-z", "--", file)
 		if err != nil {
-			return err
+			return fmt.Errorf("erro ao ler o stage de %s: %w", file, err)
 		}
-
-		// Adicionar cada arquivo modificado ao stage
-		for _, file := range changedFiles {
-			_, err = w.Add(file)
-			if err != nil {
-				return fmt.Errorf("erro ao adicionar arquivo %s: %w", file, err)
-			}
+		if entry = strings.TrimRight(entry, "\x00"); entry != "" {
+			saved.WriteString(entry + "\x00")
+		} else {
+			// Remoção staged: modo 0 remove o caminho do índice na restauração
+			saved.WriteString("0 0000000000000000000000000000000000000000\t" + file + "\x00")
+		}
+	}
+	if len(others) > 0 {
+		if _, err := r.git(append([]string{"reset", "-q", "--"}, others...)...); err != nil {
+			return fmt.Errorf("erro ao separar as demais alterações: %w", err)
 		}
+		defer func() {
+			cmd := exec.Command("git", "-C", r.Path, "update-index", "-z", "--index-info")
+			cmd.Stdin = strings.NewReader(saved.String())
+			if output, restoreErr := cmd.CombinedOutput(); restoreErr != nil && err == nil {
+				err = fmt.Errorf("erro ao restaurar o stage: %v: %s", restoreErr, output)
+			}
+		}()
 	}
 
-	// Realizar o commit
-	_, err = w.Commit(message, &git.CommitOptions{})
+	if staged == "" {
+		if _, err := r.git(append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+			return fmt.Errorf("erro ao adicionar arquivos ao stage: %w", err)
+		}
+	}
+	has, err := hasStaged(r.Path)
 	if err != nil {
 		return err
 	}
+	if !has && !opts.AllowEmpty {
+		return fmt.Errorf("nenhuma alteração para commit em %s", strings.Join(paths, ", "))
+	}
+	return r.Commit(message, opts)
+}
+
+// git executa um comando git no repositório e retorna a saída padrão
+func (r *Repository) git(args ...string) (string, error) {
+	cmd := r.command(args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
 
-	return nil
+// command prepara um comando git a ser executado no repositório. Caminhos com caracteres
+// não ASCII são exibidos como estão, sem aspas nem escapes.
+func (r *Repository) command(args ...string) *exec.Cmd {
+	return exec.Command("git", append([]string{"-C", r.Path, "-c", "core.quotepath=false"}, args...)...)
 }
 
 // AddFilesToStage adiciona uma lista de arquivos para a área de stage
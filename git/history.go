@@ -0,0 +1,110 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// CurrentBranch retorna o nome curto do branch atual.
+// Retorna uma string vazia quando o HEAD está destacado ou o repositório ainda não tem commits.
+func (r *Repository) CurrentBranch() (string, error) {
+	head, err := r.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// RecentCommitMessages retorna as mensagens dos últimos n commits do branch atual, da mais
+// recente para a mais antiga. Commits de merge são ignorados.
+func (r *Repository) RecentCommitMessages(n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	head, err := r.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler HEAD: %w", err)
+	}
+
+	iter, err := r.repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+	defer iter.Close()
+
+	var messages []string
+	err = iter.ForEach(func(c *object.Commit) error {
+		if c.NumParents() > 1 {
+			return nil
+		}
+		messages = append(messages, strings.TrimSpace(c.Message))
+		if len(messages) >= n {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+	return messages, nil
+}
+
+// RecentAuthors retorna os autores dos últimos n commits do branch atual, do mais recente
+// para o mais antigo, sem repetições e sem o usuário atual (user.email)
+func (r *Repository) RecentAuthors(n int) ([]Person, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	head, err := r.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler HEAD: %w", err)
+	}
+	self, err := r.configValue("user.email")
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := r.repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+	defer iter.Close()
+
+	var people []Person
+	seen := map[string]bool{strings.ToLower(self): true}
+	count := 0
+	err = iter.ForEach(func(c *object.Commit) error {
+		if email := strings.ToLower(c.Author.Email); !seen[email] {
+			seen[email] = true
+			people = append(people, Person{Name: c.Author.Name, Email: c.Author.Email})
+		}
+		if count++; count >= n {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+	return people, nil
+}
@@ -0,0 +1,99 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// hookPath retorna o caminho do hook informado, respeitando core.hooksPath.
+// Retorna "" quando o hook não existe ou não é executável, como o git faz.
+func (r *Repository) hookPath(name string) (string, error) {
+	path, err := r.gitPath("hooks/" + name)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+		return "", nil
+	}
+	return path, nil
+}
+
+// gitPath resolve um caminho dentro do diretório .git (ex.: "hooks", "COMMIT_EDITMSG")
+func (r *Repository) gitPath(name string) (string, error) {
+	output, err := r.git("rev-parse", "--git-path", name)
+	if err != nil {
+		return "", fmt.Errorf("erro ao localizar %s: %w", name, err)
+	}
+	path := strings.TrimSpace(output)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Path, path)
+	}
+	return path, nil
+}
+
+// runHook executa o hook informado, se existir, na raiz do repositório.
+// Uma saída diferente de zero interrompe o commit e a saída do hook é incluída no erro.
+func (r *Repository) runHook(name string, args ...string) error {
+	path, err := r.hookPath(name)
+	if err != nil || path == "" {
+		return err
+	}
+
+	cmd := r.hookCommand(path, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("hook %s falhou: %w\n%s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// hookCommand prepara a execução de um hook com o ambiente que o git fornece
+func (r *Repository) hookCommand(path string, args ...string) *exec.Cmd {
+	cmd := exec.Command(path, args...)
+	cmd.Dir = r.Path
+	// GIT_EDITOR=: indica aos hooks que não há editor interativo, como em "git commit -m"
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=:")
+	return cmd
+}
+
+// runCommitMsgHooks executa os hooks prepare-commit-msg e commit-msg sobre a mensagem, que é
+// gravada em COMMIT_EDITMSG como faz o git. Retorna a mensagem, possivelmente alterada pelos hooks.
+func (r *Repository) runCommitMsgHooks(message string) (string, error) {
+	prepare, err := r.hookPath("prepare-commit-msg")
+	if err != nil {
+		return "", err
+	}
+	commitMsg, err := r.hookPath("commit-msg")
+	if err != nil {
+		return "", err
+	}
+	if prepare == "" && commitMsg == "" {
+		return message, nil
+	}
+
+	file, err := r.gitPath("COMMIT_EDITMSG")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, []byte(message), 0o644); err != nil {
+		return "", fmt.Errorf("erro ao gravar a mensagem de commit: %w", err)
+	}
+	if err := r.runHook("prepare-commit-msg", file, "message"); err != nil {
+		return "", err
+	}
+	if err := r.runHook("commit-msg", file); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler a mensagem de commit: %w", err)
+	}
+	if strings.TrimSpace(string(edited)) == "" {
+		return "", fmt.Errorf("mensagem de commit vazia após os hooks")
+	}
+	return strings.TrimRight(string(edited), "\n") + "\n", nil
+}
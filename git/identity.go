@@ -0,0 +1,127 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Person identifica o autor ou um coautor de um commit
+type Person struct {
+	Alias string // Apelido usado para citar a pessoa (apenas no arquivo de equipe)
+	Name  string
+	Email string
+}
+
+// String retorna a identidade no formato do git: "Nome <email>"
+func (p Person) String() string {
+	return p.Name + " <" + p.Email + ">"
+}
+
+// signature converte a identidade em uma assinatura do go-git com o horário atual
+func (p Person) signature() *object.Signature {
+	return &object.Signature{Name: p.Name, Email: p.Email, When: time.Now()}
+}
+
+// ParsePerson interpreta uma identidade no formato "Nome <email>"
+func ParsePerson(value string) (Person, error) {
+	value = strings.TrimSpace(value)
+	open, end := strings.LastIndex(value, "<"), strings.LastIndex(value, ">")
+	if open < 0 || end < open {
+		return Person{}, fmt.Errorf("identidade inválida: %q (use \"Nome <email>\")", value)
+	}
+	p := Person{
+		Name:  strings.TrimSpace(value[:open]),
+		Email: strings.TrimSpace(value[open+1 : end]),
+	}
+	if p.Name == "" || p.Email == "" {
+		return Person{}, fmt.Errorf("identidade inválida: %q (use \"Nome <email>\")", value)
+	}
+	return p, nil
+}
+
+// LoadRoster lê o arquivo de equipe: uma pessoa por linha, como "Nome <email>" ou
+// "apelido: Nome <email>". Linhas vazias e iniciadas por # são ignoradas.
+// Se o arquivo não existir, retorna uma lista vazia.
+func LoadRoster(file string) ([]Person, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo de equipe: %w", err)
+	}
+	defer f.Close()
+
+	var people []Person
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		alias := ""
+		if i := strings.Index(line, ":"); i >= 0 && i < strings.Index(line, "<") {
+			alias, line = strings.TrimSpace(line[:i]), line[i+1:]
+		}
+		p, err := ParsePerson(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s, linha %d: %w", file, n, err)
+		}
+		p.Alias = alias
+		people = append(people, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de equipe: %w", err)
+	}
+	return people, nil
+}
+
+// FindPerson procura uma pessoa pelo apelido, e-mail ou parte do nome (sem diferenciar
+// maiúsculas). Uma identidade completa ("Nome <email>") é aceita mesmo fora da lista.
+func FindPerson(query string, people []Person) (Person, error) {
+	if strings.Contains(query, "<") {
+		return ParsePerson(query)
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	var matches []Person
+	for _, p := range people {
+		if strings.ToLower(p.Alias) == q || strings.ToLower(p.Email) == q {
+			return p, nil
+		}
+		if strings.Contains(strings.ToLower(p.Name), q) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Person{}, fmt.Errorf("coautor não encontrado: %s", query)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, p := range matches {
+		names[i] = p.String()
+	}
+	return Person{}, fmt.Errorf("coautor ambíguo: %s (%s)", query, strings.Join(names, "; "))
+}
+
+// committer retorna a identidade de quem faz o commit, conforme a configuração do git
+func (r *Repository) committer() (Person, error) {
+	// Formato: "Nome <email> 1700000000 -0300"
+	output, err := r.git("var", "GIT_COMMITTER_IDENT")
+	if err != nil {
+		return Person{}, fmt.Errorf("erro ao obter a identidade do git: %w", err)
+	}
+	ident := strings.TrimSpace(output)
+	if i := strings.LastIndex(ident, ">"); i >= 0 {
+		ident = ident[:i+1]
+	}
+	return ParsePerson(ident)
+}
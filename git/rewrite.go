@@ -0,0 +1,325 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BackupRef guarda o HEAD anterior à última reescrita de commits; o reflog mantém os anteriores
+const BackupRef = "refs/commit-ai/backup"
+
+// emptyTree é o hash da árvore vazia, base das alterações do commit inicial do repositório
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// CommitInfo descreve um commit existente do branch atual
+type CommitInfo struct {
+	Hash    string // Hash completo
+	Parent  string // Hash do pai (vazio no commit inicial)
+	Message string // Mensagem completa
+}
+
+// Subject retorna a primeira linha da mensagem do commit
+func (c CommitInfo) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// CommitChangeSet retorna as alterações introduzidas por um commit em relação ao seu pai
+func (r *Repository) CommitChangeSet(rev string) (*ChangeSet, error) {
+	hash, err := r.resolve(rev)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.diff("diff-tree", "-p", "--root", "--no-commit-id", "--find-renames", fmt.Sprintf("-U%d", diffContext), hash)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diff de %s: %w", rev, err)
+	}
+	return &ChangeSet{Commit: hash, Files: ParseDiff(output)}, nil
+}
+
+// RangeChangeSet retorna o resultado líquido dos commits informados (do mais antigo para o
+// mais recente): as alterações entre o pai do primeiro e o último. Se o intervalo começa no
+// commit inicial, as alterações são comparadas com a árvore vazia.
+func (r *Repository) RangeChangeSet(commits []CommitInfo) (*ChangeSet, error) {
+	if len(commits) == 0 {
+		return &ChangeSet{}, nil
+	}
+	base := commits[0].Parent
+	if base == "" {
+		base = emptyTree
+	}
+	return r.DiffChangeSet(base, commits[len(commits)-1].Hash)
+}
+
+// CommitsInRange retorna os commits de um intervalo ("A..B", "A^!" para um único commit ou
+// uma revisão isolada, que equivale a "A..HEAD"), do mais antigo para o mais recente. Os commits
+// precisam estar no histórico linear do HEAD: intervalos com merges ou fora do branch atual são
+// recusados.
+func (r *Repository) CommitsInRange(spec string) ([]CommitInfo, error) {
+	if !strings.Contains(spec, "..") && !strings.HasSuffix(spec, "^!") {
+		spec += "..HEAD"
+	}
+	output, err := r.git("rev-list", "--reverse", spec)
+	if err != nil {
+		return nil, fmt.Errorf("intervalo inválido %s: %w", spec, err)
+	}
+	hashes := strings.Fields(output)
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("nenhum commit no intervalo %s", spec)
+	}
+
+	history, err := r.linearHistory(hashes[0])
+	if err != nil {
+		return nil, err
+	}
+	// O intervalo deve ser um trecho contínuo do histórico a partir do primeiro commit
+	if len(hashes) > len(history) {
+		return nil, fmt.Errorf("o intervalo %s não está no histórico linear do HEAD", spec)
+	}
+	for i, hash := range hashes {
+		if history[i].Hash != hash {
+			return nil, fmt.Errorf("o intervalo %s não está no histórico linear do HEAD", spec)
+		}
+	}
+	return history[:len(hashes)], nil
+}
+
+// linearHistory retorna os commits do primeiro informado até o HEAD, do mais antigo para o
+// mais recente, e falha se houver merges no caminho ou se o commit não for ancestral do HEAD
+func (r *Repository) linearHistory(first string) ([]CommitInfo, error) {
+	if _, err := r.git("merge-base", "--is-ancestor", first, "HEAD"); err != nil {
+		return nil, fmt.Errorf("o commit %.7s não pertence ao branch atual", first)
+	}
+	spec := first + "^..HEAD"
+	if _, err := r.resolve(first + "^"); err != nil {
+		spec = "HEAD" // Commit inicial: todo o histórico
+	}
+
+	// Formato: "<hash> <pais>\x00<mensagem>\x00" por commit
+	output, err := r.git("log", "--reverse", "-z", "--format=%H %P%x00%B", spec)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+
+	var commits []CommitInfo
+	for i := 0; i+1 < len(fields); i += 2 {
+		ids := strings.Fields(fields[i])
+		if len(ids) > 2 {
+			return nil, fmt.Errorf("o histórico a partir de %.7s contém merges", first)
+		}
+		c := CommitInfo{Hash: ids[0], Message: strings.TrimRight(fields[i+1], "\n")}
+		if len(ids) == 2 {
+			c.Parent = ids[1]
+		}
+		if len(commits) > 0 && c.Parent != commits[len(commits)-1].Hash {
+			return nil, fmt.Errorf("o histórico a partir de %.7s não é linear", first)
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// PushedRefs retorna os branches remotos que já contêm o commit informado
+func (r *Repository) PushedRefs(hash string) ([]string, error) {
+	output, err := r.git("for-each-ref", "--contains", hash, "--format=%(refname:short)", "refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao verificar branches remotos: %w", err)
+	}
+	return strings.Fields(output), nil
+}
+
+// Reword reescreve as mensagens dos commits informados (pelo hash) e recria os commits
+// seguintes até o HEAD, preservando árvores, autores e datas de autoria. O HEAD anterior é
+// guardado em BackupRef. A área de stage e o diretório de trabalho não são alterados.
+// Os hooks prepare-commit-msg e commit-msg são executados sobre as novas mensagens e os
+// rodapés da mensagem original (ex.: Signed-off-by) são mantidos.
+func (r *Repository) Reword(messages map[string]string, opts CommitOptions) error {
+	var first string
+	for hash := range messages {
+		if first == "" || r.isAncestor(hash, first) {
+			first = hash
+		}
+	}
+	if first == "" {
+		return nil
+	}
+	history, err := r.linearHistory(first)
+	if err != nil {
+		return err
+	}
+	head := history[len(history)-1].Hash
+
+	sign, err := r.signingFormat()
+	if err != nil {
+		return err
+	}
+
+	parent := history[0].Parent
+	for _, c := range history {
+		message := c.Message + "\n"
+		author := (*Person)(nil)
+		if newMessage, ok := messages[c.Hash]; ok {
+			if message, err = r.rewordMessage(c.Message, newMessage, opts); err != nil {
+				return err
+			}
+			author = opts.Author
+		}
+		if parent, err = r.recommit(c.Hash, parent, message, author, sign != ""); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.git("update-ref", "--create-reflog", "-m", "commit-ai: backup", BackupRef, head); err != nil {
+		return fmt.Errorf("erro ao criar backup: %w", err)
+	}
+	if _, err := r.git("update-ref", "-m", "commit-ai: reword", "HEAD", parent, head); err != nil {
+		return fmt.Errorf("erro ao atualizar o branch: %w", err)
+	}
+	return nil
+}
+
+// Squash substitui os commits informados (do mais antigo para o mais recente, terminando no
+// HEAD) por um único commit com a mensagem informada, como "git reset --soft" seguido de
+// "git commit". O HEAD anterior é guardado em BackupRef. Alterações já staged são recusadas,
+// para não entrarem no commit combinado; as do diretório de trabalho ficam como estão.
+func (r *Repository) Squash(commits []CommitInfo, message string, opts CommitOptions) error {
+	if len(commits) < 2 {
+		return fmt.Errorf("são necessários ao menos dois commits para combinar")
+	}
+	base, head := commits[0].Parent, commits[len(commits)-1].Hash
+	current, err := r.resolve("HEAD")
+	if err != nil {
+		return err
+	}
+	if current != head {
+		return fmt.Errorf("o intervalo precisa terminar no HEAD")
+	}
+	staged, err := hasStaged(r.Path)
+	if err != nil {
+		return err
+	}
+	if staged {
+		return fmt.Errorf("há alterações staged; faça commit ou remova-as do stage antes de combinar")
+	}
+	diffBase := base
+	if diffBase == "" {
+		diffBase = emptyTree
+	}
+	if _, err := r.git("diff", "--quiet", diffBase, head); err == nil {
+		return fmt.Errorf("os commits não têm alterações líquidas")
+	}
+
+	if _, err := r.git("update-ref", "--create-reflog", "-m", "commit-ai: backup", BackupRef, head); err != nil {
+		return fmt.Errorf("erro ao criar backup: %w", err)
+	}
+	// Sem pai (intervalo a partir do commit inicial), o branch volta a não ter commits e o
+	// commit combinado passa a ser o inicial; o índice é mantido nos dois casos
+	if base == "" {
+		if _, err := r.git("update-ref", "-m", "commit-ai: squash", "-d", "HEAD"); err != nil {
+			return fmt.Errorf("erro ao voltar para o início do branch: %w", err)
+		}
+	} else if _, err := r.git("reset", "-q", "--soft", base); err != nil {
+		return fmt.Errorf("erro ao voltar para %.7s: %w", base, err)
+	}
+	if err := r.Commit(message, opts); err != nil {
+		// Restaurar o branch para não deixar os commits desfeitos apenas no stage
+		if _, resetErr := r.git("update-ref", "-m", "commit-ai: squash", "HEAD", head); resetErr != nil {
+			return fmt.Errorf("%w (e erro ao restaurar o HEAD; use %s: %v)", err, BackupRef, resetErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// rewordMessage prepara a nova mensagem de um commit: mantém os rodapés da mensagem original,
+// adiciona os rodapés pedidos e executa os hooks de mensagem
+func (r *Repository) rewordMessage(original string, message string, opts CommitOptions) (string, error) {
+	message = strings.TrimRight(message, "\n") + "\n"
+
+	output, err := r.gitInput(original+"\n", "interpret-trailers", "--only-trailers", "--only-input")
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler rodapés: %w", err)
+	}
+	var args []string
+	for _, trailer := range strings.Split(strings.TrimSpace(output), "\n") {
+		if trailer != "" {
+			args = append(args, "--trailer", trailer)
+		}
+	}
+	if len(args) > 0 {
+		cmdArgs := append([]string{"interpret-trailers", "--if-exists", "addIfDifferent"}, args...)
+		if message, err = r.gitInput(message, cmdArgs...); err != nil {
+			return "", fmt.Errorf("erro ao adicionar rodapés: %w", err)
+		}
+	}
+
+	if message, err = r.addTrailers(message, opts); err != nil {
+		return "", err
+	}
+	return r.runCommitMsgHooks(message)
+}
+
+// recommit cria uma cópia do commit com outro pai e outra mensagem, preservando a árvore, a
+// data de autoria e o autor original (ou o autor informado). Retorna o hash do novo commit.
+func (r *Repository) recommit(hash string, parent string, message string, author *Person, sign bool) (string, error) {
+	// Formato: "<nome>\x00<email>\x00<data>"
+	ident, err := r.git("log", "-1", "--format=%an%x00%ae%x00%ad", "--date=raw", hash)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler o autor de %.7s: %w", hash, err)
+	}
+	parts := strings.SplitN(strings.TrimRight(ident, "\n"), "\x00", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("autor inválido em %.7s", hash)
+	}
+	if author != nil {
+		parts[0], parts[1] = author.Name, author.Email
+	}
+	env := []string{"GIT_AUTHOR_NAME=" + parts[0], "GIT_AUTHOR_EMAIL=" + parts[1], "GIT_AUTHOR_DATE=" + parts[2]}
+
+	args := []string{"commit-tree", hash + "^{tree}", "-F", "-"}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	if sign {
+		args = append(args, "-S")
+	}
+	cmd := r.command(args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = strings.NewReader(message)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("erro ao recriar o commit %.7s: %w: %s", hash, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// resolve retorna o hash completo de uma revisão
+func (r *Repository) resolve(rev string) (string, error) {
+	output, err := r.git("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("revisão inválida: %s", rev)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// isAncestor indica se o primeiro commit é ancestral do segundo
+func (r *Repository) isAncestor(ancestor string, descendant string) bool {
+	_, err := r.git("merge-base", "--is-ancestor", ancestor, descendant)
+	return err == nil
+}
+
+// gitInput executa um comando git com a entrada informada e retorna a saída padrão
+func (r *Repository) gitInput(input string, args ...string) (string, error) {
+	cmd := r.command(args...)
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(output), nil
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InferScope deduz o escopo de Conventional Commits a partir dos arquivos alterados.
+// Para cada arquivo, o escopo vem, nesta ordem: do mapa explícito caminho -> escopo (o prefixo
+// mais longo vence), do workspace do package.json que o contém, do go.mod mais próximo abaixo
+// da raiz ou, para arquivos .go, do pacote Go. Se todos os arquivos levarem ao mesmo escopo,
+// ele é usado; se algum arquivo não tiver escopo, vale o diretório comum a todos.
+// Retorna uma string vazia quando as alterações abrangem mais de um componente.
+func (r *Repository) InferScope(cs *ChangeSet, scopes map[string]string) string {
+	if cs.IsEmpty() {
+		return ""
+	}
+
+	resolver := &scopeResolver{
+		root:       r.Path,
+		scopes:     scopes,
+		workspaces: loadWorkspaces(r.Path),
+		modules:    make(map[string]bool),
+	}
+
+	found := ""
+	unknown := false
+	paths := make([]string, len(cs.Files))
+	for i, f := range cs.Files {
+		paths[i] = f.Path
+		scope := resolver.fileScope(f.Path)
+		switch {
+		case scope == "":
+			unknown = true
+		case found == "":
+			found = scope
+		case scope != found:
+			return ""
+		}
+	}
+	if !unknown {
+		return found
+	}
+
+	dir := commonDir(paths)
+	if dir == "" {
+		return ""
+	}
+	return path.Base(dir)
+}
+
+// scopeResolver guarda o que já foi lido do repositório durante a inferência do escopo
+type scopeResolver struct {
+	root       string
+	scopes     map[string]string
+	workspaces map[string]string // Diretório do workspace -> nome do pacote
+	modules    map[string]bool   // Cache de diretórios com go.mod
+}
+
+// fileScope deduz o escopo de um único arquivo
+func (s *scopeResolver) fileScope(file string) string {
+	if scope := longestPrefix(file, s.scopes); scope != "" {
+		return scope
+	}
+	if scope := longestPrefix(file, s.workspaces); scope != "" {
+		return scope
+	}
+
+	dir := path.Dir(file)
+	for d := dir; d != "." && d != "/"; d = path.Dir(d) {
+		if s.hasModule(d) {
+			return path.Base(d)
+		}
+	}
+	if strings.HasSuffix(file, ".go") && dir != "." {
+		return path.Base(dir)
+	}
+	return ""
+}
+
+// hasModule informa se o diretório contém um go.mod
+func (s *scopeResolver) hasModule(dir string) bool {
+	has, ok := s.modules[dir]
+	if !ok {
+		_, err := os.Stat(filepath.Join(s.root, filepath.FromSlash(dir), "go.mod"))
+		has = err == nil
+		s.modules[dir] = has
+	}
+	return has
+}
+
+// longestPrefix retorna o valor associado ao maior diretório (ou arquivo) do mapa que contém o caminho
+func longestPrefix(file string, prefixes map[string]string) string {
+	best, value := -1, ""
+	for prefix, v := range prefixes {
+		prefix = strings.Trim(path.Clean(prefix), "/")
+		if prefix == "." || prefix == "" || v == "" {
+			continue
+		}
+		if (file == prefix || strings.HasPrefix(file, prefix+"/")) && len(prefix) > best {
+			best, value = len(prefix), v
+		}
+	}
+	return value
+}
+
+// commonDir retorna o maior diretório comum aos caminhos, ou uma string vazia se for a raiz
+func commonDir(paths []string) string {
+	common := strings.Split(path.Dir(paths[0]), "/")
+	for _, p := range paths[1:] {
+		parts := strings.Split(path.Dir(p), "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 || common[0] == "." || common[0] == "" {
+		return ""
+	}
+	return strings.Join(common, "/")
+}
+
+// packageJSON contém os campos do package.json usados na inferência do escopo
+type packageJSON struct {
+	Name       string          `json:"name"`
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// loadWorkspaces lê os workspaces declarados no package.json da raiz e retorna o nome de
+// cada pacote (sem o prefixo @organização/), indexado pelo diretório relativo à raiz
+func loadWorkspaces(root string) map[string]string {
+	var pkg packageJSON
+	if !readPackageJSON(filepath.Join(root, "package.json"), &pkg) || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	// "workspaces" pode ser uma lista de padrões ou um objeto com o campo "packages"
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err != nil {
+		var nested struct {
+			Packages []string `json:"packages"`
+		}
+		if err := json.Unmarshal(pkg.Workspaces, &nested); err != nil {
+			return nil
+		}
+		patterns = nested.Packages
+	}
+
+	workspaces := make(map[string]string)
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		// filepath.Glob não entende "**"; um nível de diretórios cobre o uso comum
+		pattern = strings.ReplaceAll(strings.TrimSuffix(pattern, "/"), "**", "*")
+		matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		sort.Strings(matches)
+		for _, match := range matches {
+			var member packageJSON
+			if !readPackageJSON(filepath.Join(match, "package.json"), &member) {
+				continue
+			}
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				continue
+			}
+			name := member.Name
+			if i := strings.LastIndex(name, "/"); i >= 0 {
+				name = name[i+1:]
+			}
+			if name == "" {
+				name = filepath.Base(match)
+			}
+			workspaces[filepath.ToSlash(rel)] = name
+		}
+	}
+	return workspaces
+}
+
+// readPackageJSON decodifica um package.json; retorna false se ele não existir ou for inválido
+func readPackageJSON(file string, pkg *packageJSON) bool {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, pkg) == nil
+}
@@ -0,0 +1,209 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// stageContext é a quantidade de linhas de contexto dos trechos que podem ser adicionados
+// individualmente ao stage, a mesma do "git add -p"
+const stageContext = 3
+
+// WorktreeChangeSet retorna as alterações do diretório de trabalho que ainda não estão no
+// stage, incluindo arquivos não rastreados, com trechos na granularidade do "git add -p".
+// Diferente de GetChangeSet, não depende do que já está staged; os IDs dos trechos são os
+// aceitos por StageHunks.
+func (r *Repository) WorktreeChangeSet() (*ChangeSet, error) {
+	diff, err := r.diff("diff", fmt.Sprintf("-U%d", stageContext))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter diff: %w", err)
+	}
+	cs := &ChangeSet{Files: ParseDiff(diff)}
+
+	untracked, err := r.untrackedFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range untracked {
+		cs.Files = append(cs.Files, r.untrackedChange(file))
+	}
+	return cs, nil
+}
+
+// StageHunks adiciona à área de stage apenas os trechos informados de um arquivo, como
+// "git add -p". Os IDs são os de Hunk.ID no arquivo em WorktreeChangeSet e deixam de valer
+// depois que o stage muda. O novo conteúdo do índice é montado aplicando os trechos escolhidos
+// à versão que já está no stage e gravado como um blob; o diretório de trabalho não é alterado.
+func (r *Repository) StageHunks(file string, hunkIDs []int) error {
+	if len(hunkIDs) == 0 {
+		return fmt.Errorf("nenhum trecho informado para %s", file)
+	}
+
+	entry, err := r.git("ls-files", "--stage", "--", file)
+	if err != nil {
+		return fmt.Errorf("erro ao ler o stage de %s: %w", file, err)
+	}
+	if entry == "" {
+		return r.stageUntracked(file, hunkIDs)
+	}
+	// Formato: "<modo> <objeto> <estágio>\t<caminho>"
+	fields := strings.Fields(entry)
+	if len(fields) < 3 || fields[2] != "0" {
+		return fmt.Errorf("%s tem conflitos não resolvidos", file)
+	}
+	mode := fields[0]
+
+	diff, err := r.diff("diff", fmt.Sprintf("-U%d", stageContext), "--", file)
+	if err != nil {
+		return fmt.Errorf("erro ao obter diff de %s: %w", file, err)
+	}
+	changes := ParseDiff(diff)
+	if len(changes) == 0 {
+		return fmt.Errorf("nenhuma alteração fora do stage em %s", file)
+	}
+	change := changes[0]
+	if change.Binary {
+		return fmt.Errorf("não é possível adicionar trechos de um arquivo binário: %s", file)
+	}
+
+	selected, err := selectHunks(change.Hunks, hunkIDs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	// Remoção do arquivo inteiro: retirar o caminho do índice
+	if change.Status == StatusDeleted && len(selected) == len(change.Hunks) {
+		if _, err := r.git("rm", "-q", "--cached", "--", file); err != nil {
+			return fmt.Errorf("erro ao adicionar remoção de %s: %w", file, err)
+		}
+		return nil
+	}
+
+	index, err := r.git("show", ":"+file)
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s do stage: %w", file, err)
+	}
+	content, err := applyHunks(index, selected)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return r.updateIndex(file, mode, content)
+}
+
+// stageUntracked adiciona um arquivo ainda não rastreado, que tem um único trecho (ID 0)
+func (r *Repository) stageUntracked(file string, hunkIDs []int) error {
+	if _, err := os.Lstat(filepath.Join(r.Path, file)); err != nil {
+		return fmt.Errorf("arquivo não encontrado: %s", file)
+	}
+	for _, id := range hunkIDs {
+		if id != 0 {
+			return fmt.Errorf("%s: trecho %d não existe", file, id)
+		}
+	}
+	if _, err := r.git("add", "--", file); err != nil {
+		return fmt.Errorf("erro ao adicionar arquivo %s: %w", file, err)
+	}
+	return nil
+}
+
+// updateIndex grava o conteúdo como blob e o registra no índice com o modo informado
+func (r *Repository) updateIndex(file string, mode string, content string) error {
+	cmd := r.command("hash-object", "-w", "--stdin", "--no-filters")
+	cmd.Stdin = strings.NewReader(content)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("erro ao gravar o conteúdo de %s: %w", file, err)
+	}
+	blob := strings.TrimSpace(string(output))
+	if _, err := r.git("update-index", "--cacheinfo", mode+","+blob+","+file); err != nil {
+		return fmt.Errorf("erro ao atualizar o stage de %s: %w", file, err)
+	}
+	return nil
+}
+
+// selectHunks retorna os trechos com os IDs informados, na ordem do arquivo
+func selectHunks(hunks []Hunk, ids []int) ([]Hunk, error) {
+	wanted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id < 0 || id >= len(hunks) {
+			return nil, fmt.Errorf("trecho %d não existe (o arquivo tem %d)", id, len(hunks))
+		}
+		wanted[id] = true
+	}
+
+	var selected []Hunk
+	for _, h := range hunks {
+		if wanted[h.ID] {
+			selected = append(selected, h)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].OldStart < selected[j].OldStart })
+	return selected, nil
+}
+
+// applyHunks aplica os trechos, em ordem, ao conteúdo original. As linhas de contexto e as
+// removidas precisam coincidir com o original; os trechos não escolhidos ficam de fora.
+func applyHunks(original string, hunks []Hunk) (string, error) {
+	old := splitLines(original)
+	var b strings.Builder
+	next := 0 // Próxima linha do original (índice a partir de 0) ainda não copiada
+
+	for _, h := range hunks {
+		// Em trechos que só adicionam linhas, OldStart é a linha após a qual elas entram
+		start := h.OldStart - 1
+		if h.OldLines == 0 {
+			start = h.OldStart
+		}
+		if start < next || start > len(old) {
+			return "", fmt.Errorf("trecho %d fora do arquivo", h.ID)
+		}
+		for ; next < start; next++ {
+			b.WriteString(old[next])
+		}
+
+		added := false // Indica se a última linha do trecho foi adicionada por ele
+		for _, line := range h.Lines {
+			if line == "" {
+				continue
+			}
+			text := line[1:]
+			switch line[0] {
+			case ' ', '-':
+				if next >= len(old) || strings.TrimSuffix(old[next], "\n") != text {
+					return "", fmt.Errorf("trecho %d não se aplica à versão do stage", h.ID)
+				}
+				if line[0] == ' ' {
+					b.WriteString(old[next])
+				}
+				next++
+				added = false
+			case '+':
+				b.WriteString(text + "\n")
+				added = true
+			case '\\':
+				// "\ No newline at end of file": a linha anterior não termina com quebra
+				if added {
+					s := strings.TrimSuffix(b.String(), "\n")
+					b.Reset()
+					b.WriteString(s)
+				}
+			}
+		}
+	}
+	for ; next < len(old); next++ {
+		b.WriteString(old[next])
+	}
+	return b.String(), nil
+}
+
+// splitLines divide o texto em linhas, mantendo a quebra de linha de cada uma
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,226 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lines retorna o conteúdo de um arquivo com n linhas, trocando as indicadas
+func lines(n int, changed ...int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		line := fmt.Sprintf("line %d", i)
+		for _, c := range changed {
+			if c == i {
+				line = fmt.Sprintf("changed %d", i)
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// runGit executa um comando git no diretório, sem a configuração global do usuário
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
+
+// writeFile grava um arquivo no repositório de teste
+func writeFile(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stagedLines retorna as linhas adicionadas e removidas de "git diff --cached"
+func stagedLines(t *testing.T, dir string) []string {
+	t.Helper()
+	var changed []string
+	for _, line := range strings.Split(runGit(t, dir, "diff", "--cached", "--no-color"), "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			changed = append(changed, line)
+		}
+	}
+	return changed
+}
+
+func TestStageHunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string)
+		file    string
+		hunks   []int
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "um trecho entre vários",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "big.txt", lines(100, 10, 50, 90))
+			},
+			file:  "big.txt",
+			hunks: []int{1},
+			want:  []string{"-line 50", "+changed 50"},
+		},
+		{
+			name: "trechos próximos",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "big.txt", lines(100, 10, 18))
+			},
+			file:  "big.txt",
+			hunks: []int{1},
+			want:  []string{"-line 18", "+changed 18"},
+		},
+		{
+			name: "trechos fora de ordem",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "big.txt", lines(100, 10, 50, 90))
+			},
+			file:  "big.txt",
+			hunks: []int{2, 0},
+			want:  []string{"-line 10", "+changed 10", "-line 90", "+changed 90"},
+		},
+		{
+			name: "arquivo novo não rastreado",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "new.txt", "a\nb\n")
+			},
+			file:  "new.txt",
+			hunks: []int{0},
+			want:  []string{"+a", "+b"},
+		},
+		{
+			name: "arquivo removido",
+			setup: func(t *testing.T, dir string) {
+				os.Remove(filepath.Join(dir, "small.txt"))
+			},
+			file:  "small.txt",
+			hunks: []int{0},
+			want:  []string{"-one", "-two", "-three"},
+		},
+		{
+			name: "trecho inexistente",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "big.txt", lines(100, 10, 50, 90))
+			},
+			file:    "big.txt",
+			hunks:   []int{5},
+			wantErr: true,
+		},
+		{
+			name: "índice com parte das alterações",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "big.txt", lines(100, 10))
+				runGit(t, dir, "add", "big.txt")
+				writeFile(t, dir, "big.txt", lines(100, 10, 50, 90))
+			},
+			file:  "big.txt",
+			hunks: []int{1},
+			want:  []string{"-line 10", "+changed 10", "-line 90", "+changed 90"},
+		},
+		{
+			name: "arquivo sem quebra de linha no final",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, dir, "small.txt", "one\ntwo\nthree\nfour")
+			},
+			file:  "small.txt",
+			hunks: []int{0},
+			want:  []string{"+four"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			runGit(t, dir, "init", "-q")
+			writeFile(t, dir, "big.txt", lines(100))
+			writeFile(t, dir, "small.txt", "one\ntwo\nthree\n")
+			runGit(t, dir, "add", ".")
+			runGit(t, dir, "commit", "-q", "-m", "initial")
+			tt.setup(t, dir)
+
+			before := stagedLines(t, dir)
+			repo, err := OpenRepository(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = repo.StageHunks(tt.file, tt.hunks)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("esperava erro")
+				}
+				if got := stagedLines(t, dir); !reflect.DeepEqual(got, before) {
+					t.Errorf("o stage mudou após o erro: %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stagedLines(t, dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("git diff --cached = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorktreeChangeSetHunkIDs(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	writeFile(t, dir, "big.txt", lines(100))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+
+	// Com parte das alterações no stage, os trechos são os que ainda faltam adicionar
+	writeFile(t, dir, "big.txt", lines(100, 10))
+	runGit(t, dir, "add", "big.txt")
+	writeFile(t, dir, "big.txt", lines(100, 10, 50, 90))
+
+	repo, err := OpenRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := repo.WorktreeChangeSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs.Files) != 1 || len(cs.Files[0].Hunks) != 2 {
+		t.Fatalf("esperava um arquivo com 2 trechos, obtido %+v", cs.Files)
+	}
+	id := -1
+	for _, h := range cs.Files[0].Hunks {
+		if h.OldStart <= 90 && 90 < h.OldStart+h.OldLines {
+			id = h.ID
+		}
+	}
+	if id < 0 {
+		t.Fatal("trecho da linha 90 não encontrado")
+	}
+
+	if err := repo.StageHunks("big.txt", []int{id}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"-line 10", "+changed 10", "-line 90", "+changed 90"}
+	if got := stagedLines(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("git diff --cached = %q, esperado %q", got, want)
+	}
+}
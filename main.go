@@ -2,10 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/user/commit-ai/ai"
@@ -29,8 +35,24 @@ func main() {
 	dryRunFlag := flag.Bool("dry-run", false, "Apenas mostrar a mensagem de commit sem fazer o commit")
 	repoPathFlag := flag.String("repo", "", "Caminho para o repositório Git")
 	versionFlag := flag.Bool("version", false, "Mostrar a versão do aplicativo")
-	providerFlag := flag.String("provider", "", "Provedor de IA a ser usado (openai, gemini, claude, deepseek, openrouter, grok, ollama)")
+	providerFlag := flag.String("provider", "", fmt.Sprintf("Provedor de IA a ser usado (%s)", strings.Join(ai.ProviderNames(), ", ")))
 	languageFlag := flag.String("language", "", "Idioma para a mensagem de commit (pt-br, en, es, fr, de)")
+	timeoutFlag := flag.Duration("timeout", 0, "Tempo limite de cada requisição ao provedor de IA (ex.: 30s, 2m)")
+	modelFlag := flag.String("model", "", "Modelo a ser usado pelo provedor de IA")
+	baseURLFlag := flag.String("base-url", "", "URL base da API do provedor de IA (ex.: gateway ou proxy interno)")
+	maxTokensFlag := flag.Int("max-tokens", 0, "Limite de tokens da resposta do provedor de IA")
+	temperatureFlag := flag.Float64("temperature", -1, "Temperatura de amostragem do provedor de IA (0 a 2)")
+	bodyFlag := flag.Bool("body", false, "Gerar corpo e rodapés além da linha de assunto")
+	styleFlag := flag.String("style", "", fmt.Sprintf("Estilo da mensagem de commit (%s)", strings.Join(ai.StyleNames(), ", ")))
+	splitFlag := flag.Bool("split", false, "Dividir alterações misturadas em vários commits atômicos (por arquivo; um arquivo nunca é dividido)")
+	authorFlag := flag.String("author", "", "Autor do commit no formato \"Nome <email>\"")
+	coAuthorFlag := flag.String("co-author", "", "Coautores do commit: \"Nome <email>\", apelido da equipe ou parte do nome (separados por vírgula)")
+	signOffFlag := flag.Bool("signoff", false, "Adicionar o rodapé Signed-off-by (DCO)")
+	amendFlag := flag.Bool("amend", false, "Regenerar a mensagem do último commit (HEAD)")
+	squashFlag := flag.String("squash", "", "Gerar uma mensagem única e combinar os commits de um intervalo (ex.: main..HEAD)")
+	rewordFlag := flag.String("reword", "", "Regenerar as mensagens dos commits locais de um intervalo (ex.: HEAD~3..HEAD)")
+	allowEmptyFlag := flag.Bool("allow-empty", false, "Permitir um commit sem alterações")
+	fallbackFlag := flag.String("fallback", "", "Provedores de IA tentados em ordem se o principal falhar (separados por vírgula)")
 
 	// Flags para o modo watcher
 	watcherMode := flag.Bool("watch", false, "Ativar modo watcher (monitoramento contínuo)")
@@ -57,13 +79,16 @@ func main() {
 
 	// Configurar o provedor a partir da flag, se fornecida
 	if *providerFlag != "" {
-		cfg.AIProvider = *providerFlag
+		if err := ai.ValidateProvider(*providerFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.AIProvider = strings.ToLower(strings.TrimSpace(*providerFlag))
 	}
 
-	// Configurar idioma a partir da flag, se fornecida
-	if *languageFlag != "" {
-		cfg.Language = *languageFlag
-	}
+	// Cancelar requisições em andamento ao receber Ctrl+C ou SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Verificar se a chave do Gemini está definida e usar o padrão se necessário
 	if cfg.AIProvider == "gemini" && cfg.GeminiKey == "" {
@@ -73,14 +98,124 @@ func main() {
 		}
 	}
 
-	// Verificar se é modo watcher
-	if *watcherMode {
-		cfg, err := config.LoadConfig()
-		if err != nil {
-			fmt.Printf("Erro ao carregar configuração: %v\nUtilizando configuração padrão.\n", err)
-			cfg = config.DefaultConfig()
+	// Modo de configuração
+	if *configureFlag {
+		configureApp(cfg)
+		os.Exit(0)
+	}
+
+	// As flags a seguir e o .commit-ai.json valem apenas para esta execução: são aplicados a
+	// uma cópia da configuração, que nunca é salva
+	cfg = cfg.Clone()
+
+	// Configurar a cadeia de fallback a partir da flag, se fornecida
+	if *fallbackFlag != "" {
+		cfg.FallbackProviders = nil
+		for _, name := range strings.Split(*fallbackFlag, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if err := ai.ValidateProvider(name); err != nil {
+				fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+				os.Exit(1)
+			}
+			cfg.FallbackProviders = append(cfg.FallbackProviders, name)
+		}
+	}
+
+	// Configurar o tempo limite das requisições a partir da flag, se fornecida
+	if *timeoutFlag > 0 {
+		cfg.RequestTimeout = int((*timeoutFlag + time.Second - 1) / time.Second)
+	}
+
+	// Ajustes do provedor informados por flag
+	if *modelFlag != "" || *baseURLFlag != "" || *maxTokensFlag > 0 || *temperatureFlag >= 0 {
+		settings := cfg.ProviderSettings(cfg.AIProvider)
+		if *modelFlag != "" {
+			settings.Model = *modelFlag
+		}
+		if *baseURLFlag != "" {
+			settings.BaseURL = *baseURLFlag
+		}
+		if *maxTokensFlag > 0 {
+			settings.MaxTokens = *maxTokensFlag
+		}
+		if *temperatureFlag >= 0 {
+			settings.Temperature = temperatureFlag
+		}
+		cfg.SetProviderSettings(cfg.AIProvider, settings)
+	}
+
+	// Determinar o caminho do repositório
+	repoPath := *repoPathFlag
+	if repoPath == "" {
+		repoPath = cfg.RepoPath
+		if repoPath == "" {
+			repoPath, err = os.Getwd()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Erro ao obter diretório atual: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Ajustes do repositório (.commit-ai.json)
+	repoCfg, err := config.LoadRepoConfig(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao carregar configuração do repositório: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.ApplyRepoConfig(repoCfg)
+
+	// Configurar idioma a partir da flag, se fornecida
+	if *languageFlag != "" {
+		cfg.Language = *languageFlag
+	}
+
+	// Ativar corpo e rodapés a partir da flag, se fornecida
+	if *bodyFlag {
+		cfg.Body = true
+	}
+
+	// Configurar autor, coautores e Signed-off-by a partir das flags, se fornecidas
+	if *authorFlag != "" {
+		cfg.Author = *authorFlag
+	}
+	// Como -author, -co-author substitui os coautores configurados
+	if *coAuthorFlag != "" {
+		cfg.CoAuthors = nil
+		for _, coAuthor := range strings.Split(*coAuthorFlag, ",") {
+			if coAuthor = strings.TrimSpace(coAuthor); coAuthor != "" {
+				cfg.CoAuthors = append(cfg.CoAuthors, coAuthor)
+			}
 		}
+	}
+	if *signOffFlag {
+		cfg.SignOff = true
+	}
+
+	// Configurar o estilo da mensagem a partir da flag, se fornecida
+	if *styleFlag != "" {
+		cfg.CommitStyle = strings.ToLower(strings.TrimSpace(*styleFlag))
+	}
+	style, err := ai.StyleFor(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ai.CheckScopeMode(cfg.ScopeMode); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	tickets, err := ai.TicketMatcherFor(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
 
+	// Verificar se é modo watcher
+	if *watcherMode {
 		// Configurar opções do watcher
 		options := watcher.DefaultCommitOptions()
 		options.Interval = *watchInterval
@@ -89,6 +224,7 @@ func main() {
 		options.Language = *languageFlag
 		options.Silent = *watcherSilent
 		options.DoCommit = !*dryRunFlag
+		options.AllowEmpty = *allowEmptyFlag
 		options.AutoStage = true
 
 		// Processar padrões a ignorar
@@ -96,55 +232,22 @@ func main() {
 			options.IgnorePatterns = strings.Split(*ignorePatterns, ",")
 		}
 
-		// Determinar o caminho do repositório
-		repoPath := *repoPathFlag
-		if repoPath == "" {
-			repoPath = cfg.RepoPath
-			if repoPath == "" {
-				var err error
-				repoPath, err = os.Getwd()
-				if err != nil {
-					fmt.Printf("Erro ao obter diretório atual: %v\n", err)
-					os.Exit(1)
-				}
-			}
-		}
-
 		// Iniciar o watcher
 		fmt.Printf("Iniciando modo watcher para o repositório: %s\n", repoPath)
 		fmt.Printf("Intervalo: %s, Mínimo de alterações: %d\n", options.Interval, options.MinChanges)
 		fmt.Printf("Provedor de IA: %s, Modo de commit: %v\n", options.Provider, options.DoCommit)
 
-		err = watcher.StartWatcher(repoPath, cfg, options)
+		err = watcher.StartWatcher(ctx, repoPath, cfg, options)
 		if err != nil {
 			fmt.Printf("Erro ao iniciar watcher: %v\n", err)
 			os.Exit(1)
 		}
 
-		// O watcher roda indefinidamente, então não deve chegar aqui
+		// O watcher roda até o contexto ser cancelado (Ctrl+C)
+		fmt.Println("Watcher encerrado.")
 		return
 	}
 
-	// Modo de configuração
-	if *configureFlag {
-		configureApp(cfg)
-		os.Exit(0)
-	}
-
-	// Determinar o caminho do repositório
-	repoPath := *repoPathFlag
-	if repoPath == "" {
-		repoPath = cfg.RepoPath
-		if repoPath == "" {
-			var err error
-			repoPath, err = os.Getwd()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Erro ao obter diretório atual: %v\n", err)
-				os.Exit(1)
-			}
-		}
-	}
-
 	// Exibir mensagem de dry-run se necessário
 	if *dryRunFlag {
 		fmt.Println("Modo dry-run ativado - nenhum commit será realizado")
@@ -157,6 +260,81 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Erro ao abrir repositório Git: %v\n", err)
 		os.Exit(1)
 	}
+	if cfg.SigningKey != "" {
+		if err := repo.LoadSigningKey(cfg.SigningKey, cfg.SigningPassphrase()); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	commitOpts, err := ai.CommitOptionsFor(repo, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	commitOpts.AllowEmpty = *allowEmptyFlag
+
+	// Carregar templates de prompt e o contexto do histórico
+	templates, err := ai.LoadTemplates(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao carregar templates de prompt: %v\n", err)
+		os.Exit(1)
+	}
+	branch, _ := repo.CurrentBranch()
+	recentCommits, scopes, err := ai.LoadHistory(repo, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Aviso: não foi possível ler o histórico de commits: %v\n", err)
+	}
+	branchTickets := tickets.Extract(branch)
+	base := ai.Request{
+		Language:        cfg.Language,
+		Style:           style,
+		Body:            cfg.Body,
+		Branch:          branch,
+		RecentCommits:   recentCommits,
+		Scopes:          scopes,
+		Templates:       templates,
+		Tickets:         branchTickets,
+		TicketPlacement: tickets.Placement,
+	}
+
+	// Criar provedor de IA
+	info, err := ai.Resolve(cfg.AIProvider)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.AIProvider = info.Name
+	fmt.Printf("Usando provedor %s...\n", info.DisplayName)
+	if len(cfg.FallbackProviders) > 0 {
+		fmt.Printf("Provedores de fallback: %s\n", strings.Join(cfg.FallbackProviders, " -> "))
+	}
+	provider, err := ai.NewProviderChain(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao criar provedor de IA: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Gerar título e descrição de pull request (subcomando pr)
+	if flag.Arg(0) == "pr" {
+		runPullRequest(ctx, repo, provider, base, cfg, flag.Args()[1:])
+		return
+	}
+
+	// Regenerar mensagens de commits existentes, se solicitado
+	if *amendFlag || *rewordFlag != "" {
+		spec := *rewordFlag
+		if *amendFlag {
+			spec = "HEAD^!"
+		}
+		runReword(ctx, repo, provider, base, cfg, commitOpts, spec, *dryRunFlag)
+		return
+	}
+
+	// Combinar os commits de um intervalo em um só, se solicitado
+	if *squashFlag != "" {
+		runSquash(ctx, repo, provider, base, cfg, commitOpts, *squashFlag, *dryRunFlag)
+		return
+	}
 
 	// Obter arquivos alterados
 	fmt.Println("Detectando alterações...")
@@ -167,6 +345,10 @@ func main() {
 	}
 
 	if len(changedFiles) == 0 {
+		if *allowEmptyFlag {
+			commitEmpty(repo, cfg, commitOpts, *dryRunFlag)
+			return
+		}
 		fmt.Println("Nenhuma alteração detectada para commit.")
 		os.Exit(0)
 	}
@@ -177,39 +359,21 @@ func main() {
 	}
 
 	// Obter mudanças para análise
-	changes, err := repo.GetChanges()
+	changes, err := repo.GetChangeSet()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao obter mudanças: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Criar provedor de IA
-	var provider ai.Provider
-	switch cfg.AIProvider {
-	case "openai":
-		fmt.Println("Usando provedor OpenAI...")
-		provider = ai.NewOpenAIProvider(cfg.OpenAIKey)
-	case "gemini":
-		fmt.Println("Usando provedor Gemini...")
-		provider = ai.NewGeminiProvider(cfg.GeminiKey)
-	case "claude":
-		fmt.Println("Usando provedor Claude (Anthropic)...")
-		provider = ai.NewClaudeProvider(cfg.ClaudeKey)
-	case "deepseek":
-		fmt.Println("Usando provedor DeepSeek...")
-		provider = ai.NewDeepSeekProvider(cfg.DeepSeekKey)
-	case "openrouter":
-		fmt.Println("Usando provedor OpenRouter...")
-		provider = ai.NewOpenRouterProvider(cfg.OpenRouterKey)
-	case "grok":
-		fmt.Println("Usando provedor Grok (xAI)...")
-		provider = ai.NewGrokProvider(cfg.GrokKey)
-	case "ollama":
-		fmt.Println("Usando provedor Ollama (modelo local)...")
-		provider = ai.NewOllamaProvider(cfg.OllamaURL, cfg.OllamaModel)
-	default:
-		fmt.Fprintf(os.Stderr, "Provedor de IA desconhecido: %s, usando Gemini como fallback\n", cfg.AIProvider)
-		provider = ai.NewGeminiProvider(cfg.GeminiKey)
+	// Deduzir o escopo e as mudanças incompatíveis na API Go
+	scope, enforceScope, err := ai.ScopeFor(repo, changes, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	breaking, err := ai.DetectBreakingChanges(repo, changes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Aviso: não foi possível analisar a API Go: %v\n", err)
 	}
 
 	// Mostrar idioma sendo usado
@@ -227,16 +391,48 @@ func main() {
 		idiomaTexto = "português"
 	}
 	fmt.Printf("Idioma para mensagens: %s\n", idiomaTexto)
+	fmt.Printf("Estilo das mensagens: %s\n", style.Name)
+	if scope != "" {
+		fmt.Printf("Escopo deduzido: %s\n", scope)
+	}
+	if len(branchTickets) > 0 {
+		fmt.Printf("Tickets do branch: %s\n", strings.Join(branchTickets, ", "))
+	}
+	if len(breaking) > 0 {
+		fmt.Println("Mudanças incompatíveis na API Go:")
+		for _, change := range breaking {
+			fmt.Printf("  - %s\n", change.Describe("pt-br"))
+		}
+	}
+
+	req := base
+	req.ChangeSet = changes
+	req.Scope, req.EnforceScope = scope, enforceScope
+	req.Breaking = breaking
+
+	// Dividir as alterações em commits atômicos, se solicitado
+	if *splitFlag {
+		runSplit(ctx, repo, provider, req, cfg, commitOpts, *dryRunFlag)
+		return
+	}
 
 	// Gerar mensagem de commit
 	fmt.Println("Gerando mensagem de commit com IA...")
-	commitMsg, err := provider.GenerateCommitMessage(changes, cfg.Language)
+	commitMsg, err := provider.GenerateCommitMessage(ctx, req)
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "Geração da mensagem de commit cancelada.")
+			os.Exit(130)
+		}
 		fmt.Fprintf(os.Stderr, "Erro ao gerar mensagem de commit: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Mensagem de commit gerada: %s\n", commitMsg)
+	if strings.Contains(commitMsg, "\n") {
+		fmt.Printf("Mensagem de commit gerada:\n\n%s\n\n", commitMsg)
+	} else {
+		fmt.Printf("Mensagem de commit gerada: %s\n", commitMsg)
+	}
 
 	// No modo dry-run, apenas exibir a mensagem e sair
 	if *dryRunFlag {
@@ -245,28 +441,7 @@ func main() {
 
 	// Confirmar commit
 	if !cfg.AutoCommit {
-		// Adaptar a mensagem de confirmação e opções conforme o idioma
-		var confirmPrompt string
-		var acceptedResponses []string
-
-		switch cfg.Language {
-		case "en":
-			confirmPrompt = "Make commit with this message? [Y/n]: "
-			acceptedResponses = []string{"", "y", "yes"}
-		case "es":
-			confirmPrompt = "¿Hacer commit con este mensaje? [S/n]: "
-			acceptedResponses = []string{"", "s", "si", "sí"}
-		case "fr":
-			confirmPrompt = "Effectuer un commit avec ce message? [O/n]: "
-			acceptedResponses = []string{"", "o", "oui"}
-		case "de":
-			confirmPrompt = "Commit mit dieser Nachricht durchführen? [J/n]: "
-			acceptedResponses = []string{"", "j", "ja"}
-		default: // português
-			confirmPrompt = "Fazer commit com esta mensagem? [S/n]: "
-			acceptedResponses = []string{"", "s", "sim"}
-		}
-
+		confirmPrompt, acceptedResponses := confirmation(cfg.Language)
 		fmt.Print(confirmPrompt)
 		reader := bufio.NewReader(os.Stdin)
 		confirm, _ := reader.ReadString('\n')
@@ -282,26 +457,23 @@ func main() {
 		}
 
 		if !isAccepted {
-			// Adaptar a mensagem de cancelamento ao idioma
-			switch cfg.Language {
-			case "en":
-				fmt.Println("Operation canceled by the user.")
-			case "es":
-				fmt.Println("Operación cancelada por el usuario.")
-			case "fr":
-				fmt.Println("Opération annulée par l'utilisateur.")
-			case "de":
-				fmt.Println("Vorgang vom Benutzer abgebrochen.")
-			default: // português
-				fmt.Println("Operação cancelada pelo usuário.")
-			}
+			fmt.Println(canceledMessage(cfg.Language))
 			os.Exit(0)
 		}
+
+		// Sugerir coautores, se configurado e nenhum tiver sido informado
+		if cfg.SuggestCoAuthors && len(commitOpts.CoAuthors) == 0 {
+			candidates, err := ai.CoauthorCandidates(repo, cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Aviso: não foi possível sugerir coautores: %v\n", err)
+			}
+			commitOpts.CoAuthors = askCoauthors(reader, candidates)
+		}
 	}
 
 	// Realizar commit
 	fmt.Println("Realizando commit...")
-	if err := repo.Commit(commitMsg); err != nil {
+	if err := repo.Commit(commitMsg, commitOpts); err != nil {
 		fmt.Fprintf(os.Stderr, "Erro ao fazer commit: %v\n", err)
 		os.Exit(1)
 	}
@@ -309,6 +481,108 @@ func main() {
 	fmt.Println("Commit realizado com sucesso!")
 }
 
+// confirmation retorna a pergunta de confirmação do commit e as respostas aceitas no idioma
+// informado (a resposta vazia aceita o padrão)
+func confirmation(language string) (string, []string) {
+	switch language {
+	case "en":
+		return "Make commit with this message? [Y/n]: ", []string{"", "y", "yes"}
+	case "es":
+		return "¿Hacer commit con este mensaje? [S/n]: ", []string{"", "s", "si", "sí"}
+	case "fr":
+		return "Effectuer un commit avec ce message? [O/n]: ", []string{"", "o", "oui"}
+	case "de":
+		return "Commit mit dieser Nachricht durchführen? [J/n]: ", []string{"", "j", "ja"}
+	default: // português
+		return "Fazer commit com esta mensagem? [S/n]: ", []string{"", "s", "sim"}
+	}
+}
+
+// canceledMessage retorna o aviso de operação cancelada no idioma informado
+func canceledMessage(language string) string {
+	switch language {
+	case "en":
+		return "Operation canceled by the user."
+	case "es":
+		return "Operación cancelada por el usuario."
+	case "fr":
+		return "Opération annulée par l'utilisateur."
+	case "de":
+		return "Vorgang vom Benutzer abgebrochen."
+	default: // português
+		return "Operação cancelada pelo usuário."
+	}
+}
+
+// maxCoauthorSuggestions é a quantidade máxima de coautores sugeridos
+const maxCoauthorSuggestions = 9
+
+// askCoauthors sugere os possíveis coautores e pergunta quais incluir no commit
+func askCoauthors(reader *bufio.Reader, candidates []git.Person) []git.Person {
+	if len(candidates) == 0 {
+		return nil
+	}
+	if len(candidates) > maxCoauthorSuggestions {
+		candidates = candidates[:maxCoauthorSuggestions]
+	}
+
+	fmt.Println("Coautores sugeridos:")
+	for i, p := range candidates {
+		fmt.Printf("  %d) %s\n", i+1, p)
+	}
+	fmt.Print("Incluir coautores (números separados por vírgula, Enter para nenhum): ")
+	answer, _ := reader.ReadString('\n')
+
+	var chosen []git.Person
+	for _, field := range strings.Split(answer, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err == nil && n >= 1 && n <= len(candidates) && !slices.Contains(chosen, candidates[n-1]) {
+			chosen = append(chosen, candidates[n-1])
+		}
+	}
+	return chosen
+}
+
+// commitEmpty realiza um commit sem alterações (-allow-empty), com a mensagem informada
+// pelo usuário ou a mensagem padrão
+func commitEmpty(repo *git.Repository, cfg *config.Config, opts git.CommitOptions, dryRun bool) {
+	message := emptyCommitMessage(cfg.Language)
+	if !cfg.AutoCommit && !dryRun {
+		fmt.Printf("Nenhuma alteração detectada. Mensagem do commit vazio [%s]: ", message)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer != "" {
+			message = answer
+		}
+	}
+	if dryRun {
+		fmt.Printf("Mensagem de commit: %s\n", message)
+		return
+	}
+
+	fmt.Println("Realizando commit vazio...")
+	if err := repo.Commit(message, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao fazer commit: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Commit realizado com sucesso!")
+}
+
+// emptyCommitMessage retorna a mensagem padrão de um commit vazio no idioma informado
+func emptyCommitMessage(language string) string {
+	switch language {
+	case "en":
+		return "chore: empty commit"
+	case "es":
+		return "chore: commit vacío"
+	case "fr":
+		return "chore: commit vide"
+	case "de":
+		return "chore: leerer Commit"
+	default: // português
+		return "chore: commit vazio"
+	}
+}
+
 // configureApp configura a aplicação de forma interativa
 func configureApp(cfg *config.Config) {
 	reader := bufio.NewReader(os.Stdin)
@@ -316,77 +590,75 @@ func configureApp(cfg *config.Config) {
 	fmt.Println("=== Configuração do Commit-AI ===")
 
 	// Configurar provedor de IA
-	fmt.Printf("Provedor de IA (openai/gemini/claude/deepseek/openrouter/grok/ollama) [%s]: ", cfg.AIProvider)
+	fmt.Printf("Provedor de IA (%s) [%s]: ", strings.Join(ai.ProviderNames(), "/"), cfg.AIProvider)
 	input, _ := reader.ReadString('\n')
 	input = strings.TrimSpace(strings.ToLower(input))
-	if input == "openai" || input == "gemini" || input == "claude" || input == "deepseek" || input == "openrouter" || input == "grok" || input == "ollama" {
+	if _, ok := ai.Lookup(input); ok {
 		cfg.AIProvider = input
 	}
 
-	// Configurar chave da API dependendo do provedor
-	switch cfg.AIProvider {
-	case "openai":
-		fmt.Printf("Chave da API OpenAI [%s]: ", maskAPIKey(cfg.OpenAIKey))
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.OpenAIKey = input
-		}
-	case "gemini":
-		currentKey := cfg.GeminiKey
-		if currentKey == "" {
-			currentKey = defaultGeminiAPIKey
-		}
-		fmt.Printf("Chave da API Gemini [%s]: ", maskAPIKey(currentKey))
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.GeminiKey = input
-		} else if cfg.GeminiKey == "" {
-			// Se a chave ainda não estiver configurada, usar a padrão
-			cfg.GeminiKey = defaultGeminiAPIKey
-		}
-	case "claude":
-		fmt.Printf("Chave da API Claude (Anthropic) [%s]: ", maskAPIKey(cfg.ClaudeKey))
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.ClaudeKey = input
-		}
-	case "deepseek":
-		fmt.Printf("Chave da API DeepSeek [%s]: ", maskAPIKey(cfg.DeepSeekKey))
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.DeepSeekKey = input
-		}
-	case "openrouter":
-		fmt.Printf("Chave da API OpenRouter [%s]: ", maskAPIKey(cfg.OpenRouterKey))
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.OpenRouterKey = input
+	// Configurar os campos específicos do provedor escolhido
+	if info, ok := ai.Lookup(cfg.AIProvider); ok {
+		if info.Key != nil {
+			key := info.Key(cfg)
+			currentKey := *key
+			if info.Name == "gemini" && currentKey == "" {
+				currentKey = defaultGeminiAPIKey
+			}
+			fmt.Printf("Chave da API %s [%s]: ", info.DisplayName, maskAPIKey(currentKey))
+			input, _ := reader.ReadString('\n')
+			input = strings.TrimSpace(input)
+			if input != "" {
+				*key = input
+			} else if info.Name == "gemini" && *key == "" {
+				// Se a chave ainda não estiver configurada, usar a padrão
+				*key = defaultGeminiAPIKey
+			}
 		}
-	case "grok":
-		fmt.Printf("Chave da API Grok (xAI) [%s]: ", maskAPIKey(cfg.GrokKey))
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.GrokKey = input
+
+		if info.ServerURL != nil {
+			serverURL := info.ServerURL(cfg)
+			fmt.Printf("URL do servidor %s [%s]: ", info.DisplayName, *serverURL)
+			input, _ := reader.ReadString('\n')
+			input = strings.TrimSpace(input)
+			if input != "" {
+				*serverURL = input
+			}
 		}
-	case "ollama":
-		fmt.Printf("URL do servidor Ollama [%s]: ", cfg.OllamaURL)
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.OllamaURL = input
+
+		if info.Model != nil {
+			model := info.Model(cfg)
+			fmt.Printf("Modelo %s a ser usado [%s]: ", info.DisplayName, *model)
+			input, _ := reader.ReadString('\n')
+			input = strings.TrimSpace(input)
+			if input != "" {
+				*model = input
+			}
 		}
 
-		fmt.Printf("Modelo Ollama a ser usado [%s]: ", cfg.OllamaModel)
-		input, _ = reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-		if input != "" {
-			cfg.OllamaModel = input
+		// Provedores sem valores padrão (como openai-compatible) são configurados pelo mapa providers
+		if info.BaseURL == "" && !info.Offline {
+			settings := cfg.ProviderSettings(info.Name)
+
+			fmt.Printf("URL base da API %s [%s]: ", info.DisplayName, settings.BaseURL)
+			input, _ := reader.ReadString('\n')
+			if input = strings.TrimSpace(input); input != "" {
+				settings.BaseURL = input
+			}
+
+			fmt.Printf("Modelo %s a ser usado [%s]: ", info.DisplayName, settings.Model)
+			input, _ = reader.ReadString('\n')
+			if input = strings.TrimSpace(input); input != "" {
+				settings.Model = input
+			}
+
+			fmt.Printf("Chave da API %s (opcional) [%s]: ", info.DisplayName, maskAPIKey(settings.APIKey))
+			input, _ = reader.ReadString('\n')
+			if input = strings.TrimSpace(input); input != "" {
+				settings.APIKey = input
+			}
+
+			cfg.SetProviderSettings(info.Name, settings)
 		}
 	}
 
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/user/commit-ai/ai"
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// runPullRequest implementa o subcomando pr: compara o branch atual com a referência base e
+// gera o título e a descrição do pull request a partir dos commits e do diff do branch
+func runPullRequest(ctx context.Context, repo *git.Repository, provider ai.Provider, req ai.Request, cfg *config.Config, args []string) {
+	prFlags := flag.NewFlagSet("pr", flag.ExitOnError)
+	baseFlag := prFlags.String("base", "", "Referência base do pull request (padrão: branch padrão do origin, main ou master)")
+	outputFlag := prFlags.String("output", "", "Arquivo onde gravar a descrição; o título é exibido na tela")
+	templateFlag := prFlags.String("template", "", "Template da descrição (padrão: pull_request_template.md do repositório, se houver)")
+	prFlags.Parse(args)
+
+	base := *baseFlag
+	if base == "" {
+		var err error
+		if base, err = repo.DefaultBase(); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v (use -base)\n", err)
+			os.Exit(1)
+		}
+	}
+	mergeBase, commits, err := repo.BranchCommits(base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	changes, err := repo.DiffChangeSet(mergeBase, "HEAD")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao obter mudanças: %v\n", err)
+		os.Exit(1)
+	}
+	if changes.IsEmpty() {
+		fmt.Printf("Nenhuma alteração em relação a %s.\n", base)
+		return
+	}
+	fmt.Printf("Comparando com %s: %d commit(s), %d arquivo(s) alterado(s)\n", base, len(commits), len(changes.Files))
+
+	template, err := ai.LoadPullRequestTemplate(repo.Path, *templateFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	if template != "" {
+		fmt.Println("Usando o template de pull request do repositório")
+	}
+
+	req = requestFor(repo, req, changes, cfg)
+	req.PRTemplate = template
+	for _, c := range commits {
+		req.Commits = append(req.Commits, c.Message)
+	}
+
+	fmt.Println("Gerando descrição do pull request com IA...")
+	pr, err := ai.GeneratePullRequest(ctx, provider, req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "Geração da descrição cancelada.")
+			os.Exit(130)
+		}
+		fmt.Fprintf(os.Stderr, "Erro ao gerar descrição do pull request: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *outputFlag == "" {
+		fmt.Printf("\n%s\n", pr)
+		return
+	}
+	if err := os.WriteFile(*outputFlag, []byte(pr.Body+"\n"), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao gravar a descrição: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Título: %s\n", pr.Title)
+	fmt.Printf("Descrição gravada em %s\n", *outputFlag)
+}
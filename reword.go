@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/user/commit-ai/ai"
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// runReword regenera as mensagens dos commits do intervalo a partir das alterações de cada
+// um e reescreve o histórico local, após a confirmação do usuário. Commits que já estão em
+// um branch remoto não são reescritos.
+func runReword(ctx context.Context, repo *git.Repository, provider ai.Provider, req ai.Request, cfg *config.Config, opts git.CommitOptions, spec string, dryRun bool) {
+	commits, err := repo.CommitsInRange(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Recusar commits publicados: reescrevê-los exigiria um push forçado
+	refs, err := repo.PushedRefs(commits[0].Hash)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	if len(refs) > 0 {
+		fmt.Fprintf(os.Stderr, "Erro: os commits já foram enviados para %s e não serão reescritos.\n", strings.Join(refs, ", "))
+		os.Exit(1)
+	}
+
+	// Gerar a nova mensagem de cada commit
+	messages := make(map[string]string, len(commits))
+	for i, c := range commits {
+		fmt.Printf("Gerando mensagem do commit %d de %d (%.7s)...\n", i+1, len(commits), c.Hash)
+		changes, err := repo.CommitChangeSet(c.Hash)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro ao obter mudanças: %v\n", err)
+			os.Exit(1)
+		}
+		if changes.IsEmpty() {
+			fmt.Printf("O commit %.7s não tem alterações; a mensagem será mantida.\n", c.Hash)
+			continue
+		}
+		if messages[c.Hash], err = provider.GenerateCommitMessage(ctx, requestFor(repo, req, changes, cfg)); err != nil {
+			exitOnGenerationError(err)
+		}
+	}
+	if len(messages) == 0 {
+		fmt.Println("Nenhuma mensagem para reescrever.")
+		return
+	}
+
+	fmt.Println()
+	for _, c := range commits {
+		message, ok := messages[c.Hash]
+		if !ok {
+			continue
+		}
+		fmt.Printf("%.7s %s\n     -> %s\n", c.Hash, c.Subject(), strings.ReplaceAll(message, "\n", "\n        "))
+	}
+	fmt.Println()
+
+	// No modo dry-run, apenas exibir as novas mensagens e sair
+	if dryRun {
+		return
+	}
+
+	if !cfg.AutoCommit {
+		_, acceptedResponses := confirmation(cfg.Language)
+		fmt.Print(rewordPrompt(cfg.Language, len(messages)))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !slices.Contains(acceptedResponses, strings.TrimSpace(strings.ToLower(answer))) {
+			fmt.Println(canceledMessage(cfg.Language))
+			return
+		}
+	}
+
+	fmt.Println("Reescrevendo commits...")
+	if err := repo.Reword(messages, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao reescrever commits: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d commit(s) reescrito(s) com sucesso! O HEAD anterior foi guardado em %s.\n", len(messages), git.BackupRef)
+}
+
+// rewordPrompt retorna a pergunta de confirmação da reescrita de commits no idioma informado
+func rewordPrompt(language string, n int) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf("Rewrite %d commit(s) with these messages? [Y/n]: ", n)
+	case "es":
+		return fmt.Sprintf("¿Reescribir %d commit(s) con estos mensajes? [S/n]: ", n)
+	case "fr":
+		return fmt.Sprintf("Réécrire %d commit(s) avec ces messages? [O/n]: ", n)
+	case "de":
+		return fmt.Sprintf("%d Commit(s) mit diesen Nachrichten umschreiben? [J/n]: ", n)
+	default: // português
+		return fmt.Sprintf("Reescrever %d commit(s) com estas mensagens? [S/n]: ", n)
+	}
+}
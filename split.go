@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/user/commit-ai/ai"
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// runSplit divide as alterações em commits atômicos, mostra o plano com a mensagem de cada
+// commit e realiza os commits um a um, após a confirmação do usuário
+func runSplit(ctx context.Context, repo *git.Repository, provider ai.Provider, req ai.Request, cfg *config.Config, opts git.CommitOptions, dryRun bool) {
+	fmt.Println("Planejando a divisão das alterações em commits...")
+	groups, err := ai.SplitChanges(ctx, provider, req)
+	if err != nil {
+		exitOnGenerationError(err)
+	}
+
+	// Gerar a mensagem de cada grupo
+	messages := make([]string, len(groups))
+	for i, group := range groups {
+		fmt.Printf("Gerando mensagem do commit %d de %d...\n", i+1, len(groups))
+		if messages[i], err = provider.GenerateCommitMessage(ctx, requestFor(repo, req, group, cfg)); err != nil {
+			exitOnGenerationError(err)
+		}
+	}
+
+	fmt.Printf("\nAs alterações serão divididas em %d commit(s):\n", len(groups))
+	for i, group := range groups {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(groups), strings.ReplaceAll(messages[i], "\n", "\n      "))
+		for _, f := range group.Files {
+			fmt.Printf("  - %s\n", f.Path)
+		}
+	}
+	fmt.Println()
+
+	// No modo dry-run, apenas exibir o plano e sair
+	if dryRun {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	_, acceptedResponses := confirmation(cfg.Language)
+	committed := 0
+	for i, group := range groups {
+		if !cfg.AutoCommit {
+			fmt.Print(splitPrompt(cfg.Language, i+1, len(groups)))
+			answer, _ := reader.ReadString('\n')
+			answer = strings.TrimSpace(strings.ToLower(answer))
+			if answer == "q" {
+				fmt.Println(canceledMessage(cfg.Language))
+				break
+			}
+			if !slices.Contains(acceptedResponses, answer) {
+				fmt.Printf("Commit %d ignorado; as alterações continuam pendentes.\n", i+1)
+				continue
+			}
+		}
+
+		fmt.Printf("Realizando commit %d de %d...\n", i+1, len(groups))
+		if err := repo.CommitPaths(groupPaths(group), messages[i], opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro ao fazer commit: %v\n", err)
+			os.Exit(1)
+		}
+		committed++
+	}
+
+	fmt.Printf("%d de %d commit(s) realizado(s) com sucesso!\n", committed, len(groups))
+}
+
+// requestFor adapta o pedido a outro conjunto de alterações, deduzindo de novo o escopo e as
+// mudanças incompatíveis na API Go
+func requestFor(repo *git.Repository, req ai.Request, cs *git.ChangeSet, cfg *config.Config) ai.Request {
+	var err error
+	req.ChangeSet = cs
+	if req.Scope, req.EnforceScope, err = ai.ScopeFor(repo, cs, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	if req.Breaking, err = ai.DetectBreakingChanges(repo, cs); err != nil {
+		fmt.Fprintf(os.Stderr, "Aviso: não foi possível analisar a API Go: %v\n", err)
+	}
+	return req
+}
+
+// exitOnGenerationError encerra o programa após uma falha na geração pela IA
+func exitOnGenerationError(err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "Geração da mensagem de commit cancelada.")
+		os.Exit(130)
+	}
+	fmt.Fprintf(os.Stderr, "Erro ao gerar mensagem de commit: %v\n", err)
+	os.Exit(1)
+}
+
+// splitPrompt retorna a pergunta de confirmação de cada commit da divisão no idioma informado
+func splitPrompt(language string, n int, total int) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf("Make commit %d of %d? [Y/n/q]: ", n, total)
+	case "es":
+		return fmt.Sprintf("¿Hacer el commit %d de %d? [S/n/q]: ", n, total)
+	case "fr":
+		return fmt.Sprintf("Effectuer le commit %d sur %d? [O/n/q]: ", n, total)
+	case "de":
+		return fmt.Sprintf("Commit %d von %d durchführen? [J/n/q]: ", n, total)
+	default: // português
+		return fmt.Sprintf("Fazer o commit %d de %d? [S/n/q]: ", n, total)
+	}
+}
+
+// groupPaths retorna os caminhos de um grupo, incluindo o caminho anterior dos arquivos renomeados
+func groupPaths(group *git.ChangeSet) []string {
+	var paths []string
+	for _, f := range group.Files {
+		paths = append(paths, f.Path)
+		if f.OldPath != "" && f.OldPath != f.Path {
+			paths = append(paths, f.OldPath)
+		}
+	}
+	return paths
+}
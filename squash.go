@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/user/commit-ai/ai"
+	"github.com/user/commit-ai/config"
+	"github.com/user/commit-ai/git"
+)
+
+// runSquash gera uma mensagem única para os commits do intervalo, a partir das mensagens
+// individuais e do diff líquido, e, após a confirmação do usuário, combina os commits em um
+func runSquash(ctx context.Context, repo *git.Repository, provider ai.Provider, req ai.Request, cfg *config.Config, opts git.CommitOptions, spec string, dryRun bool) {
+	commits, err := repo.CommitsInRange(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+	changes, err := repo.RangeChangeSet(commits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao obter mudanças: %v\n", err)
+		os.Exit(1)
+	}
+	if changes.IsEmpty() {
+		fmt.Println("Os commits do intervalo não têm alterações líquidas.")
+		return
+	}
+
+	fmt.Printf("Commits a combinar (%d):\n", len(commits))
+	req = requestFor(repo, req, changes, cfg)
+	for _, c := range commits {
+		fmt.Printf("  %.7s %s\n", c.Hash, c.Subject())
+		req.Squashed = append(req.Squashed, c.Message)
+	}
+
+	fmt.Println("Gerando mensagem de squash com IA...")
+	message, err := provider.GenerateCommitMessage(ctx, req)
+	if err != nil {
+		exitOnGenerationError(err)
+	}
+	fmt.Printf("Mensagem de squash gerada:\n\n%s\n\n", message)
+
+	// No modo dry-run, ou com um único commit, apenas exibir a mensagem
+	if dryRun || len(commits) < 2 {
+		return
+	}
+
+	if refs, err := repo.PushedRefs(commits[0].Hash); err == nil && len(refs) > 0 {
+		fmt.Printf("Aviso: os commits já foram enviados para %s; depois de combiná-los será preciso um push forçado.\n", strings.Join(refs, ", "))
+	}
+	if !cfg.AutoCommit {
+		_, acceptedResponses := confirmation(cfg.Language)
+		fmt.Print(squashPrompt(cfg.Language, len(commits)))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !slices.Contains(acceptedResponses, strings.TrimSpace(strings.ToLower(answer))) {
+			fmt.Println(canceledMessage(cfg.Language))
+			return
+		}
+	}
+
+	fmt.Println("Combinando commits...")
+	if err := repo.Squash(commits, message, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao combinar commits: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d commits combinados com sucesso! O HEAD anterior foi guardado em %s.\n", len(commits), git.BackupRef)
+}
+
+// squashPrompt retorna a pergunta de confirmação do squash no idioma informado
+func squashPrompt(language string, n int) string {
+	switch language {
+	case "en":
+		return fmt.Sprintf("Squash %d commits into one with this message? [Y/n]: ", n)
+	case "es":
+		return fmt.Sprintf("¿Combinar %d commits en uno con este mensaje? [S/n]: ", n)
+	case "fr":
+		return fmt.Sprintf("Combiner %d commits en un seul avec ce message? [O/n]: ", n)
+	case "de":
+		return fmt.Sprintf("%d Commits mit dieser Nachricht zu einem zusammenfassen? [J/n]: ", n)
+	default: // português
+		return fmt.Sprintf("Combinar %d commits em um com esta mensagem? [S/n]: ", n)
+	}
+}
@@ -2,6 +2,8 @@
 package watcher
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -32,6 +34,7 @@ type CommitOptions struct {
 	CommitMsg      string        // Mensagem de commit personalizada (opcional)
 	AutoStage      bool          // Adicionar arquivos automaticamente
 	DoCommit       bool          // Realizar o commit (ou apenas mostrar mensagem)
+	AllowEmpty     bool          // Permitir commits sem alterações líquidas no stage
 }
 
 // DefaultCommitOptions retorna as opções padrão para commits automáticos
@@ -101,8 +104,9 @@ func ShouldIgnoreFile(path string, ignorePatterns []string) bool {
 	return false
 }
 
-// StartWatcher inicia o monitoramento de um repositório
-func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) error {
+// StartWatcher inicia o monitoramento de um repositório e bloqueia até que ctx seja cancelado.
+// O cancelamento também interrompe requisições de IA em andamento.
+func StartWatcher(ctx context.Context, repoPath string, cfg *config.Config, options CommitOptions) error {
 	// Use os valores do config quando não especificados nas opções
 	if options.Provider == "" {
 		options.Provider = cfg.AIProvider
@@ -112,6 +116,52 @@ func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) er
 		options.Language = cfg.Language
 	}
 
+	// Resolver o provedor de IA pelo registro
+	info, err := ai.Resolve(options.Provider)
+	if err != nil {
+		return err
+	}
+	options.Provider = info.Name
+	providerCfg := *cfg
+	providerCfg.AIProvider = info.Name
+	provider, err := ai.NewProviderChain(&providerCfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar provedor de IA: %w", err)
+	}
+	style, err := ai.StyleFor(cfg)
+	if err != nil {
+		return err
+	}
+	if err := ai.CheckScopeMode(cfg.ScopeMode); err != nil {
+		return err
+	}
+	tickets, err := ai.TicketMatcherFor(cfg)
+	if err != nil {
+		return err
+	}
+	templates, err := ai.LoadTemplates(repoPath)
+	if err != nil {
+		return err
+	}
+
+	// O repositório, a chave de assinatura e as opções de commit são carregados uma única vez.
+	// Autor, coautores e Signed-off-by são resolvidos como na linha de comando; sem terminal,
+	// a sugestão interativa de coautores não é feita.
+	repo, err := git.OpenRepository(repoPath)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir repositório: %w", err)
+	}
+	if cfg.SigningKey != "" {
+		if err := repo.LoadSigningKey(cfg.SigningKey, cfg.SigningPassphrase()); err != nil {
+			return err
+		}
+	}
+	commitOpts, err := ai.CommitOptionsFor(repo, cfg)
+	if err != nil {
+		return err
+	}
+	commitOpts.AllowEmpty = options.AllowEmpty
+
 	// Criar o watcher para monitorar alterações no sistema de arquivos
 	fsWatcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -140,9 +190,6 @@ func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) er
 		return nil
 	})
 
-	// Criar canal para sinal de término
-	done := make(chan bool)
-
 	// Processar eventos de alteração
 	go func() {
 		for {
@@ -171,20 +218,22 @@ func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) er
 				}
 				log.Printf("Erro no watcher: %v", err)
 
-			case <-done:
+			case <-ctx.Done():
 				return
 			}
 		}
 	}()
 
-	// Processar eventos debounced e fazer commits quando apropriado
+	// Processar eventos debounced e fazer commits quando apropriado. O canal done é fechado
+	// quando o processamento termina, para que um commit em andamento não seja interrompido.
+	done := make(chan struct{})
 	go func() {
-		for event := range debouncedEvents {
-			// Abrir o repositório
-			repo, err := git.OpenRepository(event.RepoPath)
-			if err != nil {
-				log.Printf("Erro ao abrir repositório: %v", err)
-				continue
+		defer close(done)
+		for {
+			select {
+			case <-debouncedEvents:
+			case <-ctx.Done():
+				return
 			}
 
 			// Verificar alterações
@@ -229,40 +278,50 @@ func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) er
 					commitMsg = options.CommitMsg
 				} else {
 					// Obter alterações para análise
-					changes, err := repo.GetChanges()
+					changes, err := repo.GetChangeSet()
 					if err != nil {
 						log.Printf("Erro ao obter mudanças: %v", err)
 						continue
 					}
 
-					// Criar provedor de IA
-					var provider ai.Provider
-					switch options.Provider {
-					case "openai":
-						provider = ai.NewOpenAIProvider(cfg.OpenAIKey)
-					case "gemini":
-						provider = ai.NewGeminiProvider(cfg.GeminiKey)
-					case "claude":
-						provider = ai.NewClaudeProvider(cfg.ClaudeKey)
-					case "deepseek":
-						provider = ai.NewDeepSeekProvider(cfg.DeepSeekKey)
-					case "openrouter":
-						provider = ai.NewOpenRouterProvider(cfg.OpenRouterKey)
-					case "grok":
-						provider = ai.NewGrokProvider(cfg.GrokKey)
-					case "ollama":
-						provider = ai.NewOllamaProvider(cfg.OllamaURL, cfg.OllamaModel)
-					default:
-						provider = ai.NewGeminiProvider(cfg.GeminiKey)
-					}
-
 					// Gerar mensagem de commit
 					if !options.Silent {
 						log.Printf("Gerando mensagem de commit com IA (%s)...", options.Provider)
 					}
 
-					commitMsg, err = provider.GenerateCommitMessage(changes, options.Language)
+					branch, _ := repo.CurrentBranch()
+					recentCommits, scopes, err := ai.LoadHistory(repo, cfg)
 					if err != nil {
+						log.Printf("Erro ao ler histórico de commits: %v", err)
+					}
+					scope, enforceScope, err := ai.ScopeFor(repo, changes, cfg)
+					if err != nil {
+						log.Printf("Erro ao determinar o escopo: %v", err)
+						continue
+					}
+					breaking, err := ai.DetectBreakingChanges(repo, changes)
+					if err != nil {
+						log.Printf("Erro ao analisar a API Go: %v", err)
+					}
+					commitMsg, err = provider.GenerateCommitMessage(ctx, ai.Request{
+						ChangeSet:       changes,
+						Language:        options.Language,
+						Style:           style,
+						Body:            cfg.Body,
+						Branch:          branch,
+						RecentCommits:   recentCommits,
+						Scopes:          scopes,
+						Scope:           scope,
+						EnforceScope:    enforceScope,
+						Breaking:        breaking,
+						Templates:       templates,
+						Tickets:         tickets.Extract(branch),
+						TicketPlacement: tickets.Placement,
+					})
+					if err != nil {
+						if errors.Is(err, context.Canceled) {
+							return
+						}
 						log.Printf("Erro ao gerar mensagem de commit: %v", err)
 						continue
 					}
@@ -278,7 +337,7 @@ func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) er
 						log.Printf("Realizando commit...")
 					}
 
-					err = repo.Commit(commitMsg)
+					err = repo.Commit(commitMsg, commitOpts)
 					if err != nil {
 						log.Printf("Erro ao fazer commit: %v", err)
 						continue
@@ -300,6 +359,8 @@ func StartWatcher(repoPath string, cfg *config.Config, options CommitOptions) er
 
 	fmt.Printf("Watcher iniciado. Pressione Ctrl+C para sair.\n")
 
-	// Manter o watcher em execução
-	select {}
+	// Manter o watcher em execução até o cancelamento e aguardar o commit em andamento
+	<-ctx.Done()
+	<-done
+	return nil
 }